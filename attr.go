@@ -0,0 +1,129 @@
+package erf
+
+// KeyValue is a key/value pair of the attributes of Erf.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// With adds the given key/value pairs as attributes to Erf, and returns Erf itself.
+// The attributes are independent of arguments, so With can be used with every constructor and
+// can be called many times. If the key is already an attribute, its value is replaced.
+// The attributes are accessible through Tag and Tags like the tags attached to arguments.
+// It panics for given errors:
+// 	missing value of key
+// 	key must be string
+// 	key is empty
+// 	value is nil
+// 	tag already defined
+// In the lenient mode, it doesn't panic, the invalid key/value pairs are skipped and the problems are recorded as
+// diagnostics. See also SetLenient and TryWith.
+func (e *Erf) With(kv ...interface{}) *Erf {
+	l := Lenient()
+	e.diagnose(e.with(kv, l), l)
+	return e
+}
+
+// with adds the given key/value pairs as attributes, and returns the problems.
+// If lenient is false, it returns the first problem without adding any attribute.
+func (e *Erf) with(kv []interface{}, lenient bool) []error {
+	var diags []error
+	if len(kv)%2 != 0 {
+		if !lenient {
+			return []error{ErrMissingValue}
+		}
+		diags = append(diags, ErrMissingValue)
+		kv = kv[:len(kv)-1]
+	}
+	if len(kv) <= 0 {
+		return diags
+	}
+	attrs := make([]KeyValue, len(e.attrs), len(e.attrs)+len(kv)/2)
+	copy(attrs, e.attrs)
+	for i := 0; i < len(kv); i += 2 {
+		var diag error
+		key, ok := kv[i].(string)
+		switch {
+		case !ok:
+			diag = ErrKeyNotString
+		case key == "":
+			diag = ErrKeyEmpty
+		case kv[i+1] == nil:
+			diag = ErrValueIsNil
+		default:
+			if _, ok := e.tagIndexes[key]; ok {
+				diag = ErrTagAlreadyDefined
+			}
+		}
+		if diag != nil {
+			if !lenient {
+				return []error{diag}
+			}
+			diags = append(diags, diag)
+			continue
+		}
+		attrs = setAttr(attrs, key, kv[i+1])
+	}
+	e.attrs = attrs
+	return diags
+}
+
+// With2 is similar with With except that it returns the error interface instead of the Erf pointer.
+func (e *Erf) With2(kv ...interface{}) error {
+	return e.With(kv...)
+}
+
+// WrapWith wraps the given error as the underlying error and returns a new Erf object that has
+// the given key/value pairs as attributes, as the error interface.
+// WrapWith is similar with Wrap(err).With(kv...) except that it returns nil if err is nil.
+// It panics for the same errors with With.
+func WrapWith(err error, kv ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
+	return e.With(kv...)
+}
+
+// Attr returns the value of the attribute on the given key. It returns nil if the attribute is not found.
+func (e *Erf) Attr(key string) interface{} {
+	for _, attr := range e.attrs {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return nil
+}
+
+// Attrs returns all attributes sequentially. It returns nil if there is no attribute.
+func (e *Erf) Attrs() []KeyValue {
+	if e.attrs == nil {
+		return nil
+	}
+	result := make([]KeyValue, len(e.attrs))
+	copy(result, e.attrs)
+	return result
+}
+
+func (e *Erf) hasAttr(key string) bool {
+	for _, attr := range e.attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func setAttr(attrs []KeyValue, key string, value interface{}) []KeyValue {
+	for i := range attrs {
+		if attrs[i].Key == key {
+			attrs[i].Value = value
+			return attrs
+		}
+	}
+	return append(attrs, KeyValue{
+		Key:   key,
+		Value: value,
+	})
+}
@@ -0,0 +1,48 @@
+package erf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleErf_With() {
+	err := erf.Newf("user %q not found", "alice").Attach("name").
+		With("user_id", 42).
+		With("retry", false, "user_id", 43)
+
+	fmt.Println(err.Tags())
+	fmt.Println(err.Tag("name"), err.Tag("user_id"), err.Tag("retry"))
+	fmt.Println(err.TagIndex("name"), err.TagIndex("user_id"))
+
+	data, _ := json.Marshal(err)
+	var e2 erf.Erf
+	_ = json.Unmarshal(data, &e2)
+	fmt.Println(e2.Attrs())
+
+	// Output:
+	// [name user_id retry]
+	// alice 43 false
+	// 0 -1
+	// [{user_id 43} {retry false}]
+}
+
+func ExampleWrapWith() {
+	err := erf.WrapWith(errors.New("an example error"), "request_id", "abc123", "attempt", 2)
+	e := err.(*erf.Erf)
+
+	fmt.Println(err)
+	fmt.Println(e.Tags())
+	fmt.Println(e.Tag("request_id"), e.Tag("attempt"))
+	fmt.Println(e.Attrs())
+	fmt.Println(erf.WrapWith(nil, "request_id", "abc123") == nil)
+
+	// Output:
+	// an example error
+	// [request_id attempt]
+	// abc123 2
+	// [{request_id abc123} {attempt 2}]
+	// true
+}
@@ -0,0 +1,129 @@
+package erf
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Capture defines the stack capture policy of the functions creating Erf.
+// The positive values of Capture are the maximum number of frames to capture.
+type Capture int
+
+const (
+	// CaptureFull captures the full stack up to DefaultPCSize frames.
+	CaptureFull = Capture(-1)
+
+	// CaptureNone doesn't capture the stack.
+	CaptureNone = Capture(0)
+
+	// CaptureCaller captures only the frame of the caller.
+	CaptureCaller = Capture(1)
+)
+
+// CaptureEnv is the name of the environment variable that sets the default Capture when the program starts.
+// The value of the environment variable is parsed by using ParseCapture.
+const CaptureEnv = "ERF_CAPTURE"
+
+var defaultCapture = int64(CaptureFull)
+
+func init() {
+	if s, ok := os.LookupEnv(CaptureEnv); ok {
+		if c, err := ParseCapture(s); err == nil {
+			SetDefaultCapture(c)
+		}
+	}
+}
+
+// DefaultCapture returns the default Capture that is used by the functions creating Erf.
+// It is CaptureFull unless it is changed by SetDefaultCapture or the environment variable named CaptureEnv.
+func DefaultCapture() Capture {
+	return Capture(atomic.LoadInt64(&defaultCapture))
+}
+
+// SetDefaultCapture sets the default Capture that is used by the functions creating Erf.
+// Negative values are same with CaptureFull.
+func SetDefaultCapture(c Capture) {
+	if c < 0 {
+		c = CaptureFull
+	}
+	atomic.StoreInt64(&defaultCapture, int64(c))
+}
+
+// ParseCapture parses the given string as Capture.
+// The string can be "full", "none", "off", "caller" or a non-negative integer as number of frames.
+func ParseCapture(s string) (Capture, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "full":
+		return CaptureFull, nil
+	case "none", "off":
+		return CaptureNone, nil
+	case "caller":
+		return CaptureCaller, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return CaptureNone, errors.New("invalid capture")
+	}
+	return Capture(n), nil
+}
+
+// String is implementation of fmt.Stringer.
+func (c Capture) String() string {
+	switch {
+	case c < 0:
+		return "full"
+	case c == CaptureNone:
+		return "none"
+	case c == CaptureCaller:
+		return "caller"
+	}
+	return strconv.Itoa(int(c))
+}
+
+// New is similar with the function New except that it captures the stack by using Capture c.
+func (c Capture) New(text string) *Erf {
+	e := newText(text)
+	e.initialize(4, c)
+	return e
+}
+
+// Newf is similar with the function Newf except that it captures the stack by using Capture c.
+func (c Capture) Newf(format string, args ...interface{}) *Erf {
+	e := newf(format, args...)
+	e.initialize(4, c)
+	return e
+}
+
+// Errorf is similar with the function Errorf except that it captures the stack by using Capture c.
+func (c Capture) Errorf(format string, a ...interface{}) error {
+	e := newf(format, a...)
+	e.initialize(4, c)
+	return e
+}
+
+// Wrap is similar with the function Wrap except that it captures the stack by using Capture c.
+func (c Capture) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4, c)
+	return e
+}
+
+// Wrapp is similar with the function Wrapp except that it captures the stack by using Capture c.
+func (c Capture) Wrapp(perr *error) {
+	if perr == nil {
+		return
+	}
+	err := *perr
+	if err == nil {
+		return
+	}
+	e := newWrap(err)
+	e.initialize(4, c)
+	*perr = e
+}
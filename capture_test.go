@@ -0,0 +1,74 @@
+package erf_test
+
+import (
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleCapture() {
+	e := erf.CaptureNone.New("an example erf error without stack")
+	err := erf.CaptureCaller.Errorf("we have an example error: %w", e)
+
+	fmt.Println("captured")
+	fmt.Printf("%v %v\n\n", err.(*erf.Erf).Captured(), e.Captured())
+
+	fmt.Println("the lengths of the captured program counters.")
+	for _, c := range []erf.Capture{erf.CaptureNone, erf.CaptureCaller} {
+		fmt.Println(c, c.Newf("an example erf error on %q", "foo").PCLen())
+	}
+	fmt.Println(erf.CaptureFull, erf.CaptureFull.Newf("an example erf error on %q", "foo").PCLen() > 1)
+	fmt.Println()
+
+	fmt.Println("the caller frame of the methods.")
+	perr := error(e)
+	erf.CaptureCaller.Wrapp(&perr)
+	for _, err := range []error{
+		erf.CaptureCaller.Newf("an example erf error on %q", "foo"),
+		erf.CaptureCaller.Errorf("we have an example error: %w", e),
+		erf.CaptureCaller.Wrap(e),
+		perr,
+	} {
+		e := err.(*erf.Erf)
+		fmt.Println(e.PCLen(), e.StackTrace().Caller(0).Function)
+	}
+	fmt.Println()
+
+	fmt.Println("the default capture.")
+	erf.SetDefaultCapture(erf.CaptureNone)
+	fmt.Println(erf.DefaultCapture(), erf.New("an example erf error").Captured())
+	erf.SetDefaultCapture(-2)
+	fmt.Println(erf.DefaultCapture(), erf.New("an example erf error").Captured())
+
+	// Output:
+	// captured
+	// true false
+	//
+	// the lengths of the captured program counters.
+	// none 0
+	// caller 1
+	// full true
+	//
+	// the caller frame of the methods.
+	// 1 github.com/goinsane/erf_test.ExampleCapture
+	// 1 github.com/goinsane/erf_test.ExampleCapture
+	// 1 github.com/goinsane/erf_test.ExampleCapture
+	// 1 github.com/goinsane/erf_test.ExampleCapture
+	//
+	// the default capture.
+	// none false
+	// full true
+}
+
+func ExampleParseCapture() {
+	for _, s := range []string{"full", "off", "caller", "8"} {
+		c, _ := erf.ParseCapture(s)
+		fmt.Println(c)
+	}
+
+	// Output:
+	// full
+	// none
+	// caller
+	// 8
+}
@@ -0,0 +1,47 @@
+package erf
+
+import (
+	"io"
+)
+
+// Close closes c and merges the error returned by c.Close into the error in the given pointer.
+// Close should be deferred like the following:
+// 	func Foo() (err error) {
+// 		f, err := os.Open("foo")
+// 		...
+// 		defer erf.Close(&err, f)
+// 		...
+// 	}
+// If c.Close fails, Close wraps the error into a new Erf object capturing the stack of the caller.
+// If *perr is nil, the new Erf object is put onto the given pointer. Otherwise, both errors are merged by using
+// Append, so neither error is lost. If perr is nil, the error of c.Close is discarded.
+// Close doesn't affect if c is nil.
+func Close(perr *error, c io.Closer) {
+	if c == nil {
+		return
+	}
+	cleanup(perr, c.Close())
+}
+
+// Defer calls fn and merges the error returned by fn into the error in the given pointer.
+// Defer is similar with Close except that it calls fn instead of closing an io.Closer.
+// Defer doesn't affect if fn is nil.
+func Defer(perr *error, fn func() error) {
+	if fn == nil {
+		return
+	}
+	cleanup(perr, fn())
+}
+
+func cleanup(perr *error, err error) {
+	if err == nil || perr == nil {
+		return
+	}
+	e := newWrap(err)
+	e.initialize(5, DefaultCapture())
+	if *perr == nil {
+		*perr = e
+		return
+	}
+	*perr = Append(*perr, e)
+}
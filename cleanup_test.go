@@ -0,0 +1,64 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+type exampleCloser struct{}
+
+func (c exampleCloser) Close() error {
+	return errors.New("an example close error")
+}
+
+func exampleCloseFunc() (err error) {
+	defer erf.Close(&err, exampleCloser{})
+	return erf.New("an example erf error")
+}
+
+func ExampleClose() {
+	err := exampleCloseFunc()
+
+	fmt.Println("list all error messages.")
+	fmt.Printf("%v\n", err)
+
+	// Output:
+	// list all error messages.
+	// 2 errors occurred:
+	// 	1. an example erf error
+	// 	2. an example close error
+}
+
+func exampleDeferFunc(primary error) (err error) {
+	defer erf.Defer(&err, func() error {
+		return errors.New("an example cleanup error")
+	})
+	return primary
+}
+
+func ExampleDefer() {
+	err := exampleDeferFunc(nil)
+
+	fmt.Println("the cleanup error is returned as an Erf capturing the caller.")
+	fmt.Println(err)
+	fmt.Println(err.(*erf.Erf).StackTrace().Caller(0).Function)
+
+	errFoo := erf.New("an example erf error")
+	err = exampleDeferFunc(errFoo)
+
+	fmt.Println("the cleanup error is merged with the primary error.")
+	fmt.Println(errors.Is(err, errFoo))
+	fmt.Printf("%v\n", err)
+
+	// Output:
+	// the cleanup error is returned as an Erf capturing the caller.
+	// an example cleanup error
+	// github.com/goinsane/erf_test.exampleDeferFunc
+	// the cleanup error is merged with the primary error.
+	// true
+	// 2 errors occurred:
+	// 	1. an example erf error
+	// 	2. an example cleanup error
+}
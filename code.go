@@ -0,0 +1,262 @@
+package erf
+
+import (
+	"sync"
+)
+
+// Kind is the hierarchical kind of errors, like NotFound or InvalidArgument.
+// Kind implements error, so it can be used as the target of errors.Is. An Erf object matches the Kind that it has
+// and all ancestors of the Kind.
+type Kind struct {
+	name   string
+	parent *Kind
+}
+
+// Predefined kinds.
+var (
+	KindInternal           = NewKind("internal", nil)
+	KindInvalidArgument    = NewKind("invalid_argument", nil)
+	KindNotFound           = NewKind("not_found", nil)
+	KindAlreadyExists      = NewKind("already_exists", nil)
+	KindPermissionDenied   = NewKind("permission_denied", nil)
+	KindUnauthenticated    = NewKind("unauthenticated", nil)
+	KindFailedPrecondition = NewKind("failed_precondition", nil)
+	KindResourceExhausted  = NewKind("resource_exhausted", nil)
+	KindCanceled           = NewKind("canceled", nil)
+	KindDeadlineExceeded   = NewKind("deadline_exceeded", nil)
+	KindUnavailable        = NewKind("unavailable", nil)
+	KindUnimplemented      = NewKind("unimplemented", nil)
+)
+
+// Code is a machine-readable error code that has a unique number, a unique name and an optional Kind.
+// Code implements error, so it can be used as the target of errors.Is. An Erf object matches the Code that it has,
+// and the Kind of the Code.
+type Code struct {
+	number int
+	name   string
+	kind   *Kind
+}
+
+var registry = struct {
+	mu            sync.RWMutex
+	kinds         map[string]*Kind
+	codesByNumber map[int]*Code
+	codesByName   map[string]*Code
+	sentinels     map[string]*Erf
+	sentinelNames map[*Erf]string
+}{
+	kinds:         make(map[string]*Kind),
+	codesByNumber: make(map[int]*Code),
+	codesByName:   make(map[string]*Code),
+	sentinels:     make(map[string]*Erf),
+	sentinelNames: make(map[*Erf]string),
+}
+
+// NewKind creates and registers a new Kind with the given name and the parent Kind.
+// If parent is nil, the new Kind is a root Kind.
+// It panics if the name is empty or a Kind with the same name is already registered.
+func NewKind(name string, parent *Kind) *Kind {
+	if name == "" {
+		panic("kind name is empty")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.kinds[name]; ok {
+		panic("kind already registered")
+	}
+	k := &Kind{
+		name:   name,
+		parent: parent,
+	}
+	registry.kinds[name] = k
+	return k
+}
+
+// LookupKind returns the registered Kind with the given name. It returns nil if the Kind is not found.
+func LookupKind(name string) *Kind {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.kinds[name]
+}
+
+// Error is implementation of error. It returns the name of Kind.
+func (k *Kind) Error() string {
+	return k.name
+}
+
+// Name returns the name of Kind.
+func (k *Kind) Name() string {
+	return k.name
+}
+
+// Parent returns the parent of Kind. It returns nil if Kind is a root Kind.
+func (k *Kind) Parent() *Kind {
+	return k.parent
+}
+
+// Is reports whether target is Kind or an ancestor of Kind. It is used by errors.Is.
+func (k *Kind) Is(target error) bool {
+	t, ok := target.(*Kind)
+	if !ok || t == nil {
+		return false
+	}
+	for p := k; p != nil; p = p.parent {
+		if p == t {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterCode creates and registers a new Code with the given number, name and Kind. Kind can be nil.
+// It panics if the name is empty or a Code with the same number or name is already registered.
+func RegisterCode(number int, name string, kind *Kind) *Code {
+	if name == "" {
+		panic("code name is empty")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.codesByNumber[number]; ok {
+		panic("code already registered")
+	}
+	if _, ok := registry.codesByName[name]; ok {
+		panic("code already registered")
+	}
+	c := &Code{
+		number: number,
+		name:   name,
+		kind:   kind,
+	}
+	registry.codesByNumber[number] = c
+	registry.codesByName[name] = c
+	return c
+}
+
+// LookupCode returns the registered Code with the given number. It returns nil if the Code is not found.
+func LookupCode(number int) *Code {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.codesByNumber[number]
+}
+
+// LookupCodeByName returns the registered Code with the given name. It returns nil if the Code is not found.
+func LookupCodeByName(name string) *Code {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.codesByName[name]
+}
+
+// Error is implementation of error. It returns the name of Code.
+func (c *Code) Error() string {
+	return c.name
+}
+
+// Number returns the number of Code.
+func (c *Code) Number() int {
+	return c.number
+}
+
+// Name returns the name of Code.
+func (c *Code) Name() string {
+	return c.name
+}
+
+// Kind returns the Kind of Code. It returns nil if Code doesn't have a Kind.
+func (c *Code) Kind() *Kind {
+	return c.kind
+}
+
+// WithCode sets the given Code to Erf, and returns Erf itself.
+// If Erf doesn't have a Kind set by WithKind, the Kind of Code is used as the Kind of Erf.
+func (e *Erf) WithCode(c *Code) *Erf {
+	e.code = c
+	return e
+}
+
+// WithKind sets the given Kind to Erf, and returns Erf itself.
+func (e *Erf) WithKind(k *Kind) *Erf {
+	e.kind = k
+	return e
+}
+
+// WrapCode wraps the given error as the underlying error and returns a new Erf object that has the given Code,
+// as the error interface. WrapCode is similar with Wrap(err).WithCode(c) except that it returns nil if err is nil.
+func WrapCode(err error, c *Code) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
+	return e.WithCode(c)
+}
+
+// WrapKind wraps the given error as the underlying error and returns a new Erf object that has the given Kind,
+// as the error interface. WrapKind is similar with Wrap(err).WithKind(k) except that it returns nil if err is nil.
+func WrapKind(err error, k *Kind) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
+	return e.WithKind(k)
+}
+
+// Code returns the Code of Erf. It returns nil if Erf doesn't have a Code.
+func (e *Erf) Code() *Code {
+	return e.code
+}
+
+// Kind returns the Kind of Erf set by WithKind, or the Kind of the Code of Erf.
+// It returns nil if Erf doesn't have a Kind.
+func (e *Erf) Kind() *Kind {
+	if e.kind != nil {
+		return e.kind
+	}
+	if e.code != nil {
+		return e.code.kind
+	}
+	return nil
+}
+
+// CodeOf returns the nearest Code in the error tree of err. The errors are visited in the same order with Walk.
+// It returns nil if the Code is not found.
+func CodeOf(err error) *Code {
+	var c *Code
+	Walk(err, func(err error, depth int) bool {
+		if c != nil {
+			return false
+		}
+		if e, ok := err.(interface{ Code() *Code }); ok {
+			c = e.Code()
+		}
+		return c == nil
+	})
+	return c
+}
+
+// KindOf returns the nearest Kind in the error tree of err. The errors are visited in the same order with Walk.
+// It returns nil if the Kind is not found.
+func KindOf(err error) *Kind {
+	var k *Kind
+	Walk(err, func(err error, depth int) bool {
+		if k != nil {
+			return false
+		}
+		if e, ok := err.(interface{ Kind() *Kind }); ok {
+			k = e.Kind()
+		}
+		return k == nil
+	})
+	return k
+}
+
+func (e *Erf) isCodeOrKind(target error) bool {
+	switch t := target.(type) {
+	case *Code:
+		return t != nil && e.code == t
+	case *Kind:
+		k := e.Kind()
+		return t != nil && k != nil && k.Is(t)
+	}
+	return false
+}
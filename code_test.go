@@ -0,0 +1,76 @@
+package erf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+var (
+	kindExampleUserNotFound = erf.NewKind("example_user_not_found", erf.KindNotFound)
+	codeExampleUserNotFound = erf.RegisterCode(1001, "example_user_not_found", kindExampleUserNotFound)
+)
+
+func ExampleRegisterCode() {
+	e := erf.Newf("user %q not found", "alice").WithCode(codeExampleUserNotFound)
+	err := erf.Wrap(fmt.Errorf("we have an example error: %w", e))
+
+	fmt.Println(errors.Is(err, codeExampleUserNotFound))
+	fmt.Println(errors.Is(err, kindExampleUserNotFound), errors.Is(err, erf.KindNotFound))
+	fmt.Println(errors.Is(err, erf.KindInvalidArgument))
+	fmt.Println(erf.CodeOf(err).Number(), erf.KindOf(err).Name())
+
+	data, _ := json.Marshal(e)
+	var e2 erf.Erf
+	_ = json.Unmarshal(data, &e2)
+	fmt.Println(errors.Is(&e2, codeExampleUserNotFound), errors.Is(&e2, erf.KindNotFound))
+
+	// Output:
+	// true
+	// true true
+	// false
+	// 1001 example_user_not_found
+	// true true
+}
+
+func ExampleWrapKind() {
+	errFoo := errors.New("an example error")
+	err := erf.WrapKind(errFoo, kindExampleUserNotFound)
+
+	fmt.Println(errors.Is(err, errFoo), errors.Is(err, erf.KindNotFound), errors.Is(err, erf.KindInvalidArgument))
+	fmt.Println(erf.KindOf(err).Name(), erf.CodeOf(err) == nil)
+
+	err = erf.WrapCode(errFoo, erf.LookupCode(1001))
+
+	fmt.Println(errors.Is(err, codeExampleUserNotFound), errors.Is(err, erf.KindNotFound))
+	fmt.Println(erf.CodeOf(err) == erf.LookupCodeByName("example_user_not_found"), erf.KindOf(err).Name())
+
+	fmt.Println(erf.WrapKind(nil, erf.KindNotFound) == nil, erf.WrapCode(nil, codeExampleUserNotFound) == nil)
+
+	// Output:
+	// true true false
+	// example_user_not_found true
+	// true true
+	// true example_user_not_found
+	// true true
+}
+
+func ExampleErf_Code() {
+	err := erf.CaptureNone.Newf("request failed: %w",
+		erf.CaptureNone.New("user not found").WithCode(codeExampleUserNotFound)).WithKind(erf.KindInternal)
+
+	fmt.Println(err.Code() == nil, err.Kind().Name())
+	printLines(fmt.Sprintf("%+x", err))
+
+	// Output:
+	// true internal
+	// 	request failed: user not found
+	// * stack trace not captured
+	// = kind "internal"
+	//
+	// 	user not found
+	// * stack trace not captured
+	// = code 1001 "example_user_not_found" kind "example_user_not_found"
+}
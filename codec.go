@@ -0,0 +1,626 @@
+package erf
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"unicode/utf8"
+)
+
+// CodecVersion is the version of the binary encoding used by EncodeBinary and DecodeBinary.
+//
+// The binary encoding is compact to carry the errors in HTTP headers or RPC metadata.
+// It starts with the byte 'E', the version byte, the flags byte and the number of errors as uvarint.
+// The bit 0 of the flags byte is set if the errors were truncated by the size limit.
+// The errors are listed in the same order with the field "errors" of the JSON schema described in JSONVersion,
+// and each error has the flags byte, the message, the indexes of the wrapped errors, and
+// the optional code, kind, sentinel name, tags and frames.
+// The error that replaces the dropped errors by size limit has only the flags byte with the bit 6 set.
+// The strings are encoded with their lengths, and the repeated strings are encoded as references to
+// the first occurrences. The values of tags are encoded as strings by using fmt.Sprintf("%v", value).
+// The arguments of Erf aren't encoded.
+const CodecVersion = 1
+
+const (
+	codecMagic = 'E'
+
+	codecTruncated = 1 << 0
+
+	codecNodeErf            = 1 << 0
+	codecNodeCode           = 1 << 1
+	codecNodeKind           = 1 << 2
+	codecNodeSentinel       = 1 << 3
+	codecNodeFrames         = 1 << 4
+	codecNodeStackTruncated = 1 << 5
+	codecNodeTruncated      = 1 << 6
+)
+
+// CodecTruncatedText is the message of the error that replaces the dropped errors by size limit.
+const CodecTruncatedText = "error chain truncated"
+
+// CodecOptions is the options of EncodeBinary and EncodeBase64.
+type CodecOptions struct {
+	// MaxSize is the maximum size of the encoded data in bytes. If MaxSize is 0, there is no limit.
+	// If the encoded data exceeds MaxSize, the frames are dropped first, and then the errors are dropped one by one
+	// from the leaves of each branch, the deepest first. The errors that aren't sentinel errors are dropped before
+	// the sentinel errors, and the wrapping errors between them are dropped before the sentinel errors too, so
+	// the decoded error keeps matching the sentinel errors by using errors.Is as long as possible. The dropped leaves
+	// are replaced with an error that has the message CodecTruncatedText. At last, the message of the first error
+	// is truncated, and its tags are dropped.
+	MaxSize int
+
+	// Frames enables encoding the stack traces.
+	Frames bool
+
+	// MaxFrames is the maximum number of frames of each stack trace. If MaxFrames is 0, there is no limit.
+	MaxFrames int
+}
+
+type codecNode struct {
+	flags    byte
+	message  string
+	wrapped  []int
+	code     *Code
+	kind     *Kind
+	sentinel string
+	tags     []KeyValue
+	frames   []StackCaller
+}
+
+// EncodeBinary encodes err and all of wrapped errors by using the binary encoding described in CodecVersion.
+// If opts is nil, the zero CodecOptions is used.
+func EncodeBinary(err error, opts *CodecOptions) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("error is nil")
+	}
+	if opts == nil {
+		opts = &CodecOptions{}
+	}
+	nodes := collectCodecNodes(err, opts)
+	data := encodeCodecNodes(nodes, 0)
+	if opts.MaxSize <= 0 || len(data) <= opts.MaxSize {
+		return data, nil
+	}
+	for i := range nodes {
+		nodes[i].flags &^= codecNodeFrames | codecNodeStackTruncated
+		nodes[i].frames = nil
+	}
+	if data = encodeCodecNodes(nodes, 0); len(data) <= opts.MaxSize {
+		return data, nil
+	}
+	t := newCodecTree(nodes, 0)
+	for t.truncate() {
+		if data = encodeCodecNodes(t.nodes(), codecTruncated); len(data) <= opts.MaxSize {
+			return data, nil
+		}
+	}
+	root := nodes[0]
+	root.wrapped = nil
+	root.tags = nil
+	message := root.message
+	for {
+		root.message = message
+		if data = encodeCodecNodes([]codecNode{root}, codecTruncated); len(data) <= opts.MaxSize {
+			return data, nil
+		}
+		if message == "" {
+			return nil, errors.New("max size too small")
+		}
+		n := len(message) - (len(data) - opts.MaxSize)
+		if n >= len(message) {
+			n = len(message) - 1
+		}
+		if n < 0 {
+			n = 0
+		}
+		message = message[:n]
+		for !utf8.ValidString(message) {
+			message = message[:len(message)-1]
+		}
+	}
+}
+
+// EncodeBase64 is similar with EncodeBinary except that it encodes the binary data by using base64 URL encoding
+// without padding. MaxSize of opts limits the length of the encoded string.
+func EncodeBase64(err error, opts *CodecOptions) (string, error) {
+	if opts != nil && opts.MaxSize > 0 {
+		opts2 := *opts
+		opts2.MaxSize = base64.RawURLEncoding.DecodedLen(opts.MaxSize)
+		opts = &opts2
+	}
+	data, err := EncodeBinary(err, opts)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeBinary decodes the binary data encoded by EncodeBinary, and returns the first error as Erf.
+// If the first error isn't an Erf, it is wrapped into a new Erf without capturing the stack.
+// The decoded Erf objects match the registered sentinel errors and codes by using errors.Is.
+// The decoded frames don't have program counters, the tags are decoded as attributes.
+func DecodeBinary(data []byte) (*Erf, error) {
+	r := &codecReader{
+		data: data,
+	}
+	if r.byte() != codecMagic {
+		return nil, errors.New("invalid binary data")
+	}
+	if version := r.byte(); version != CodecVersion {
+		return nil, fmt.Errorf("unsupported codec version %d", version)
+	}
+	_ = r.byte()
+	count := r.uvarint()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if count <= 0 || count > uint64(len(r.data)) {
+		return nil, errors.New("invalid error count")
+	}
+	nodes := make([]codecNode, count)
+	for i := range nodes {
+		nodes[i] = r.node()
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+	decoded := make([]error, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		var de error
+		switch len(node.wrapped) {
+		case 0:
+			de = &decodedError{
+				text: node.message,
+			}
+		case 1:
+			if node.wrapped[0] <= i || node.wrapped[0] >= len(nodes) {
+				return nil, errors.New("wrapped index out of range")
+			}
+			de = &decodedError{
+				text: node.message,
+				err:  decoded[node.wrapped[0]],
+			}
+		default:
+			errs := make([]error, 0, len(node.wrapped))
+			for _, index := range node.wrapped {
+				if index <= i || index >= len(nodes) {
+					return nil, errors.New("wrapped index out of range")
+				}
+				errs = append(errs, decoded[index])
+			}
+			de = &decodedErrors{
+				text: node.message,
+				errs: errs,
+			}
+		}
+		if node.flags&codecNodeErf == 0 {
+			decoded[i] = de
+			continue
+		}
+		e2 := &Erf{
+			err:   de,
+			kind:  node.kind,
+			attrs: node.tags,
+		}
+		if node.code != nil {
+			e2.code = node.code
+			if e2.kind == e2.code.kind {
+				e2.kind = nil
+			}
+		}
+		if node.flags&codecNodeFrames != 0 {
+			e2.syntheticST = NewStackTraceFromCallers(node.frames...)
+			e2.captured = true
+			e2.truncated = node.flags&codecNodeStackTruncated != 0
+			e2.syntheticST.truncated = e2.truncated
+		}
+		if node.sentinel != "" {
+			e2.origin = LookupSentinel(node.sentinel)
+		}
+		decoded[i] = e2
+	}
+	if e, ok := decoded[0].(*Erf); ok {
+		return e, nil
+	}
+	return newWrap(decoded[0]), nil
+}
+
+// DecodeBase64 is similar with DecodeBinary except that it decodes the string encoded by EncodeBase64.
+func DecodeBase64(s string) (*Erf, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBinary(data)
+}
+
+// MarshalBinary is implementation of encoding.BinaryMarshaler.
+// MarshalBinary encodes Erf and all of wrapped errors by using EncodeBinary with the frames, without size limit.
+func (e *Erf) MarshalBinary() ([]byte, error) {
+	return EncodeBinary(e, &CodecOptions{
+		Frames: true,
+	})
+}
+
+// UnmarshalBinary is implementation of encoding.BinaryUnmarshaler.
+// UnmarshalBinary decodes Erf and all of wrapped errors by using DecodeBinary.
+func (e *Erf) UnmarshalBinary(data []byte) error {
+	top, err := DecodeBinary(data)
+	if err != nil {
+		return err
+	}
+	e.assign(top)
+	return nil
+}
+
+func collectCodecNodes(err error, opts *CodecOptions) []codecNode {
+	var nodes []codecNode
+	var add func(err error) int
+	add = func(err error) int {
+		index := len(nodes)
+		node := codecNode{
+			message: err.Error(),
+		}
+		if e, ok := err.(TracedError); ok {
+			node.flags |= codecNodeErf
+			if e2, ok := e.(interface{ Code() *Code }); ok {
+				if node.code = e2.Code(); node.code != nil {
+					node.flags |= codecNodeCode
+				}
+			}
+			if e2, ok := e.(interface{ Kind() *Kind }); ok {
+				if node.kind = e2.Kind(); node.kind != nil {
+					node.flags |= codecNodeKind
+				}
+			}
+			if e2, ok := e.(*Erf); ok {
+				if node.sentinel = e2.sentinelName(); node.sentinel != "" {
+					node.flags |= codecNodeSentinel
+				}
+			}
+			for _, tag := range e.Tags() {
+				node.tags = append(node.tags, KeyValue{
+					Key:   tag,
+					Value: fmt.Sprintf("%v", e.Tag(tag)),
+				})
+			}
+			if st := e.StackTrace(); opts.Frames && st != nil {
+				node.flags |= codecNodeFrames
+				node.frames = st.callers
+				if st.Truncated() {
+					node.flags |= codecNodeStackTruncated
+				}
+				if opts.MaxFrames > 0 && len(node.frames) > opts.MaxFrames {
+					node.frames = node.frames[:opts.MaxFrames]
+					node.flags |= codecNodeStackTruncated
+				}
+			}
+		}
+		nodes = append(nodes, node)
+		for _, err2 := range unwrapErrors(err) {
+			wrapped := add(err2)
+			nodes[index].wrapped = append(nodes[index].wrapped, wrapped)
+		}
+		return index
+	}
+	add(err)
+	return nodes
+}
+
+// codecTree is the tree form of the codec nodes, that is used to truncate the errors.
+type codecTree struct {
+	node        codecNode
+	children    []*codecTree
+	placeholder bool
+}
+
+func newCodecTree(nodes []codecNode, index int) *codecTree {
+	t := &codecTree{
+		node: nodes[index],
+	}
+	for _, index2 := range nodes[index].wrapped {
+		t.children = append(t.children, newCodecTree(nodes, index2))
+	}
+	return t
+}
+
+// nodes returns the codec nodes of the tree in depth-first order.
+func (t *codecTree) nodes() []codecNode {
+	var nodes []codecNode
+	var add func(t *codecTree) int
+	add = func(t *codecTree) int {
+		index := len(nodes)
+		node := t.node
+		node.wrapped = nil
+		nodes = append(nodes, node)
+		for _, t2 := range t.children {
+			wrapped := add(t2)
+			nodes[index].wrapped = append(nodes[index].wrapped, wrapped)
+		}
+		return index
+	}
+	add(t)
+	return nodes
+}
+
+// leaf reports whether the tree doesn't have any child except the placeholder.
+func (t *codecTree) leaf() bool {
+	for _, t2 := range t.children {
+		if !t2.placeholder {
+			return false
+		}
+	}
+	return true
+}
+
+// truncate drops an error from the tree, and reports whether an error was dropped.
+// The errors are dropped in the following order: the leaves that aren't sentinel errors, the wrapping errors that
+// aren't sentinel errors, and the sentinel errors. In each step, the deepest error is dropped first.
+// A dropped leaf is replaced with a placeholder, unless its parent already has a placeholder.
+// The children of a dropped wrapping error are moved to its parent.
+func (t *codecTree) truncate() bool {
+	var parent, target *codecTree
+	var index, rank, depth int
+	var find func(t *codecTree, d int)
+	find = func(t *codecTree, d int) {
+		for i, t2 := range t.children {
+			if t2.placeholder {
+				continue
+			}
+			r := 0
+			if t2.node.sentinel != "" {
+				r = 2
+			} else if !t2.leaf() {
+				r = 1
+			}
+			if target == nil || r < rank || (r == rank && d >= depth) {
+				parent, target, index, rank, depth = t, t2, i, r, d
+			}
+			find(t2, d+1)
+		}
+	}
+	find(t, 1)
+	if target == nil {
+		return false
+	}
+	children := make([]*codecTree, 0, len(parent.children)+len(target.children))
+	children = append(children, parent.children[:index]...)
+	if rank == 1 {
+		children = append(children, target.children...)
+	} else {
+		children = append(children, &codecTree{
+			node: codecNode{
+				flags:   codecNodeTruncated,
+				message: CodecTruncatedText,
+			},
+			placeholder: true,
+		})
+	}
+	children = append(children, parent.children[index+1:]...)
+	parent.children = children[:0]
+	hasPlaceholder := false
+	for _, t2 := range children {
+		if t2.placeholder {
+			if hasPlaceholder {
+				continue
+			}
+			hasPlaceholder = true
+		}
+		parent.children = append(parent.children, t2)
+	}
+	return true
+}
+
+func encodeCodecNodes(nodes []codecNode, flags byte) []byte {
+	w := &codecWriter{
+		data: make([]byte, 0, 256),
+		strs: make(map[string]int),
+	}
+	w.data = append(w.data, codecMagic, CodecVersion, flags)
+	w.uvarint(uint64(len(nodes)))
+	for _, node := range nodes {
+		w.data = append(w.data, node.flags)
+		if node.flags&codecNodeTruncated != 0 {
+			continue
+		}
+		w.str(node.message)
+		w.uvarint(uint64(len(node.wrapped)))
+		for _, index := range node.wrapped {
+			w.uvarint(uint64(index))
+		}
+		if node.flags&codecNodeErf == 0 {
+			continue
+		}
+		if node.flags&codecNodeCode != 0 {
+			w.varint(int64(node.code.number))
+			w.str(node.code.name)
+		}
+		if node.flags&codecNodeKind != 0 {
+			w.str(node.kind.name)
+		}
+		if node.flags&codecNodeSentinel != 0 {
+			w.str(node.sentinel)
+		}
+		w.uvarint(uint64(len(node.tags)))
+		for _, tag := range node.tags {
+			w.str(tag.Key)
+			w.str(tag.Value.(string))
+		}
+		if node.flags&codecNodeFrames != 0 {
+			w.uvarint(uint64(len(node.frames)))
+			for _, frame := range node.frames {
+				w.str(frame.Function)
+				w.str(frame.File)
+				w.uvarint(uint64(frame.Line))
+				w.uvarint(uint64(frame.PC - frame.Entry))
+			}
+		}
+	}
+	return w.data
+}
+
+type codecWriter struct {
+	data []byte
+	strs map[string]int
+}
+
+func (w *codecWriter) uvarint(x uint64) {
+	w.data = binary.AppendUvarint(w.data, x)
+}
+
+func (w *codecWriter) varint(x int64) {
+	w.data = binary.AppendVarint(w.data, x)
+}
+
+// str writes the length of s and s, or the reference to the first occurrence of s.
+func (w *codecWriter) str(s string) {
+	if index, ok := w.strs[s]; ok {
+		w.uvarint(uint64(index)<<1 | 1)
+		return
+	}
+	w.strs[s] = len(w.strs)
+	w.uvarint(uint64(len(s)) << 1)
+	w.data = append(w.data, s...)
+}
+
+type codecReader struct {
+	data []byte
+	strs []string
+	err  error
+}
+
+func (r *codecReader) fail() {
+	if r.err == nil {
+		r.err = errors.New("invalid binary data")
+	}
+	r.data = nil
+}
+
+func (r *codecReader) byte() byte {
+	if len(r.data) < 1 {
+		r.fail()
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *codecReader) uvarint() uint64 {
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *codecReader) varint() int64 {
+	x, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *codecReader) int() int {
+	x := r.uvarint()
+	if x > math.MaxInt32 {
+		r.fail()
+		return 0
+	}
+	return int(x)
+}
+
+func (r *codecReader) str() string {
+	x := r.uvarint()
+	if x&1 != 0 {
+		index := x >> 1
+		if index >= uint64(len(r.strs)) {
+			r.fail()
+			return ""
+		}
+		return r.strs[index]
+	}
+	n := x >> 1
+	if n > uint64(len(r.data)) {
+		r.fail()
+		return ""
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	r.strs = append(r.strs, s)
+	return s
+}
+
+func (r *codecReader) node() codecNode {
+	node := codecNode{
+		flags: r.byte(),
+	}
+	if node.flags&codecNodeTruncated != 0 {
+		node.message = CodecTruncatedText
+		return node
+	}
+	node.message = r.str()
+	if n := r.uvarint(); n > uint64(len(r.data)) {
+		r.fail()
+	} else if n > 0 {
+		node.wrapped = make([]int, 0, n)
+		for i := uint64(0); i < n; i++ {
+			node.wrapped = append(node.wrapped, r.int())
+		}
+	}
+	if node.flags&codecNodeErf == 0 || r.err != nil {
+		return node
+	}
+	var number int
+	var name string
+	if node.flags&codecNodeCode != 0 {
+		number, name = int(r.varint()), r.str()
+	}
+	if node.flags&codecNodeKind != 0 {
+		node.kind = decodeKind(r.str())
+	}
+	if node.flags&codecNodeCode != 0 {
+		node.code = decodeCode(number, name, node.kind)
+	}
+	if node.flags&codecNodeSentinel != 0 {
+		node.sentinel = r.str()
+	}
+	if n := r.uvarint(); n > uint64(len(r.data)) {
+		r.fail()
+	} else if n > 0 {
+		for i := uint64(0); i < n; i++ {
+			key, value := r.str(), r.str()
+			if key == "" {
+				continue
+			}
+			node.tags = setAttr(node.tags, key, value)
+		}
+	}
+	if node.flags&codecNodeFrames != 0 {
+		if n := r.uvarint(); n > uint64(len(r.data)) {
+			r.fail()
+		} else if n > 0 {
+			node.frames = make([]StackCaller, 0, n)
+			for i := uint64(0); i < n; i++ {
+				node.frames = append(node.frames, StackCaller{
+					Frame: runtime.Frame{
+						Function: r.str(),
+						File:     r.str(),
+						Line:     r.int(),
+						PC:       uintptr(r.uvarint()),
+					},
+				})
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,92 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goinsane/erf"
+)
+
+var errExampleCodecNotFound = erf.RegisterSentinel("erf_test.errExampleCodecNotFound", erf.NewSentinel("not found"))
+
+func ExampleEncodeBase64() {
+	e := erf.Newf("user %q: %w", "alice", errExampleCodecNotFound.Here()).Attach("name").
+		WithKind(erf.KindNotFound)
+	err := erf.Wrap(e)
+
+	s, _ := erf.EncodeBase64(err, &erf.CodecOptions{
+		Frames:    true,
+		MaxFrames: 4,
+	})
+	e2, _ := erf.DecodeBase64(s)
+
+	fmt.Println(e2)
+	fmt.Println(errors.Is(e2, errExampleCodecNotFound), errors.Is(e2, erf.KindNotFound))
+	fmt.Println(erf.LookupTag(e2, "name"))
+	fmt.Println(e2.StackTrace().Len() > 0)
+
+	// Output:
+	// user "alice": not found
+	// true true
+	// alice true
+	// true
+}
+
+func ExampleCodecOptions() {
+	err := erf.Wrap(erf.New(strings.TrimSpace(strings.Repeat("an example erf error ", 3))))
+	for i := 0; i < 3; i++ {
+		err = erf.Wrap(fmt.Errorf("wrapped #%d: %w", i, err))
+	}
+
+	data, _ := erf.EncodeBinary(err, &erf.CodecOptions{
+		MaxSize: 160,
+	})
+	e2, _ := erf.DecodeBinary(data)
+
+	fmt.Println(len(data) <= 160)
+	erf.Walk(e2, func(err error, depth int) bool {
+		fmt.Println(depth, err)
+		return true
+	})
+
+	// Output:
+	// true
+	// 0 wrapped #2: wrapped #1: wrapped #0: an example erf error an example erf error an example erf error
+	// 1 wrapped #2: wrapped #1: wrapped #0: an example erf error an example erf error an example erf error
+	// 2 error chain truncated
+}
+
+var (
+	errExampleCodecA = erf.RegisterSentinel("codec_a", erf.NewSentinel("a"))
+	errExampleCodecB = erf.RegisterSentinel("codec_b", erf.NewSentinel("b"))
+)
+
+func ExampleDecodeBinary() {
+	err := erf.Errorf("root %w / %w", erf.Wrap(erf.Wrap(errExampleCodecA.Here())), errExampleCodecB.Here())
+	data, _ := erf.EncodeBinary(err, nil)
+	fmt.Println(len(data))
+
+	for _, maxSize := range []int{50, 40} {
+		data, _ := erf.EncodeBinary(err, &erf.CodecOptions{
+			MaxSize: maxSize,
+		})
+		e2, _ := erf.DecodeBinary(data)
+		fmt.Println(len(data) <= maxSize, errors.Is(e2, errExampleCodecA), errors.Is(e2, errExampleCodecB))
+		erf.Walk(e2, func(err error, depth int) bool {
+			fmt.Println(depth, err)
+			return true
+		})
+	}
+
+	// Output:
+	// 56
+	// true true true
+	// 0 root a / b
+	// 1 a
+	// 1 b
+	// true true false
+	// 0 root a / b
+	// 1 a
+	// 1 error chain truncated
+}
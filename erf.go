@@ -5,8 +5,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -17,12 +18,24 @@ var (
 
 // Erf is an error type that wraps the underlying error that stores and formats the stack trace.
 type Erf struct {
-	err        error
-	format     string
-	args       []interface{}
-	tags       []string
-	tagIndexes map[string]int
-	pc         []uintptr
+	err         error
+	format      string
+	args        []interface{}
+	tags        []string
+	tagIndexes  map[string]int
+	attrs       []KeyValue
+	diags       []error
+	code        *Code
+	kind        *Kind
+	pc          []uintptr
+	captured    bool
+	truncated   bool
+	syntheticST *StackTrace
+	st          unsafe.Pointer // *StackTrace, resolved lazily and cached atomically
+	returnPC    []uintptr
+	returnST    *StackTrace
+	spawn       *spawn
+	origin      *Erf
 }
 
 // Error is implementation of error.
@@ -31,6 +44,10 @@ func (e *Erf) Error() string {
 }
 
 // Unwrap returns the underlying error.
+// It returns nil if the underlying error wraps multiple errors, use UnwrapMulti for this case.
+// Erf doesn't implement the method "Unwrap() []error". errors.Is and errors.As see the multiple wrapped errors by
+// the methods Is and As, but generic walkers outside of them that use only "Unwrap() []error" don't see the branches.
+// Use UnwrapMulti or Walk to reach all branches.
 func (e *Erf) Unwrap() error {
 	if err, ok := e.err.(WrappedError); ok {
 		return err.Unwrap()
@@ -38,18 +55,58 @@ func (e *Erf) Unwrap() error {
 	return nil
 }
 
+// UnwrapMulti returns all errors that wrapped by the underlying error.
+// It returns multiple errors if Erf was created with multiple '%w' verbs or it wraps a multi error,
+// and returns nil if the underlying error doesn't wrap any error.
+func (e *Erf) UnwrapMulti() []error {
+	return unwrapErrors(e.err)
+}
+
 // UnwrapAll returns all errors using Unwrap method. The first element in the returned value is e.
+// If an error in the tree wraps multiple errors, UnwrapAll lists all errors of the tree in depth-first order.
 func (e *Erf) UnwrapAll() []error {
-	result := make([]error, 0, 4096)
-	for err := error(e); err != nil; {
+	result := make([]error, 0, 16)
+	Walk(e, func(err error, depth int) bool {
 		result = append(result, err)
-		if wErr, ok := err.(WrappedError); ok {
-			err = wErr.Unwrap()
-		} else {
-			err = nil
+		return true
+	})
+	return result
+}
+
+// Is reports whether target is the original Erf that e was derived from by Trace, Tracep or Here,
+// the Code of e, the Kind of e or an ancestor of the Kind,
+// or any error that wrapped by the underlying error wrapping multiple errors matches target.
+// It is used by errors.Is.
+func (e *Erf) Is(target error) bool {
+	if e.origin != nil && target == error(e.origin) {
+		return true
+	}
+	if e.isCodeOrKind(target) {
+		return true
+	}
+	if _, ok := e.err.(WrappedError); ok {
+		return false
+	}
+	for _, err := range e.UnwrapMulti() {
+		if errors.Is(err, target) {
+			return true
 		}
 	}
-	return result
+	return false
+}
+
+// As finds the first error that matches target in the errors that wrapped by the underlying error wrapping
+// multiple errors. It is used by errors.As.
+func (e *Erf) As(target interface{}) bool {
+	if _, ok := e.err.(WrappedError); ok {
+		return false
+	}
+	for _, err := range e.UnwrapMulti() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
 }
 
 // Copy creates a shallow copy of Erf.
@@ -63,16 +120,32 @@ func (e *Erf) CopyByTop(top int) *Erf {
 	if e == nil {
 		return nil
 	}
-	if top < 0 || top > len(e.pc) {
+	size := len(e.pc)
+	if e.syntheticST != nil {
+		size = e.syntheticST.Len()
+	}
+	if top < 0 || top > size {
 		panic("top out of range")
 	}
 	e2 := &Erf{
-		err:        e.err,
-		format:     e.format,
-		args:       nil,
-		tags:       nil,
-		tagIndexes: nil,
-		pc:         nil,
+		err:         e.err,
+		format:      e.format,
+		args:        nil,
+		tags:        nil,
+		tagIndexes:  nil,
+		attrs:       nil,
+		diags:       nil,
+		code:        e.code,
+		kind:        e.kind,
+		pc:          nil,
+		captured:    e.captured,
+		truncated:   e.truncated,
+		syntheticST: nil,
+		st:          nil,
+		returnPC:    nil,
+		returnST:    e.returnST,
+		spawn:       e.spawn,
+		origin:      e.origin,
 	}
 	if e.args != nil {
 		e2.args = make([]interface{}, len(e.args))
@@ -88,28 +161,46 @@ func (e *Erf) CopyByTop(top int) *Erf {
 			e2.tagIndexes[key] = val
 		}
 	}
+	if e.attrs != nil {
+		e2.attrs = make([]KeyValue, len(e.attrs))
+		copy(e2.attrs, e.attrs)
+	}
+	if e.diags != nil {
+		e2.diags = make([]error, len(e.diags))
+		copy(e2.diags, e.diags)
+	}
 	if e.pc != nil {
 		e2.pc = make([]uintptr, len(e.pc)-top)
 		copy(e2.pc, e.pc[top:])
 	}
+	if e.returnPC != nil {
+		e2.returnPC = make([]uintptr, len(e.returnPC))
+		copy(e2.returnPC, e.returnPC)
+	}
+	if e.syntheticST != nil {
+		e2.syntheticST = NewStackTraceFromCallers(e.syntheticST.callers[top:]...)
+		e2.syntheticST.truncated = e.syntheticST.truncated
+	}
 	return e2
 }
 
 // Format is implementation of fmt.Formatter.
-// Format lists error messages and appends StackTrace's for underlying Erf and all of wrapped Erf's,
+// Format lists error messages and appends StackTrace's for underlying Erf and all of wrapped TracedError's,
 // line by line with given format.
+// If an error wraps multiple errors, the wrapped errors are shown as branches of the tree
+// by using one more padding char than the error.
 //
 // For '%v' (also '%s'):
 // 	%v       just show the first error message without padding and indent.
 //
 // For '%x' and '%X':
 // 	%x       list all error messages by using indent and show StackTrace of errors by using format '%+s'.
-// 	%+x      similar with '%x', also shows tags.
+// 	%+x      similar with '%x', also shows codes, kinds, tags, attributes and diagnostics.
 // 	% x      list all error messages by using indent and show StackTrace of errors by using format '% s'.
 // 	%#x      list all error messages by using indent and show StackTrace of errors by using format '%#s'.
 // 	% #x     list all error messages by using indent and show StackTrace of errors by using format '% #s'.
 // 	%X       show the first error message by using indent and show the StackTrace of error by using format '%+s'.
-// 	%+X      similar with '%X', also shows tags.
+// 	%+X      similar with '%X', also shows codes, kinds, tags, attributes and diagnostics.
 // 	% X      show the first error message by using indent and show the StackTrace of error by using format '% s'.
 // 	%#X      show the first error message by using indent and show the StackTrace of error by using format '%#s'.
 // 	% #X     show the first error message by using indent and show the StackTrace of error by using format '% #s'.
@@ -125,79 +216,129 @@ func (e *Erf) CopyByTop(top int) *Erf {
 // 	% 4.x    same with '% x', padding 4, indent 0.
 // 	%#4.3x   same with '%#x', padding 4, indent 3.
 // 	% #4.3x  same with '% #x', padding 4, indent 3.
+//
+// If the stack wasn't captured or the StackTrace is empty, a line starting with '*' is shown instead of StackTrace.
+// If Erf has a return trace, the line "< return trace" and the return trace are shown after StackTrace.
+// If Erf has spawn points, the line "> spawned at" and the stack trace are shown for each spawn point.
+// If Erf has diagnostics recorded in the lenient mode, a line starting with '!' is shown for each diagnostic.
 func (e *Erf) Format(f fmt.State, verb rune) {
-	buf := bytes.NewBuffer(make([]byte, 0, 4096))
 	switch verb {
 	case 's', 'v':
-		buf.WriteString(e.err.Error())
+		_, _ = io.WriteString(f, e.err.Error())
+		return
 	case 'x', 'X':
-		format := "%+"
-		for _, r := range []rune{' ', '#'} {
-			if f.Flag(int(r)) {
-				format += string(r)
-			}
+	default:
+		return
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	pad, wid, prec := getPadWidPrec(f)
+	idx := 0
+	walkErrors(e, 0, 0, func(err error, depth, branch int) bool {
+		if idx > 0 && verb == 'X' {
+			return false
 		}
-		pad, wid, prec := getPadWidPrec(f)
-		format += fmt.Sprintf("%d.%ds", wid, prec)
-		padding, indent := bytes.Repeat([]byte{pad}, wid), bytes.Repeat([]byte{pad}, prec)
-		for idx, err := range e.UnwrapAll() {
-			if idx > 0 {
-				buf.WriteRune('\n')
-			}
-			if e2, ok := err.(*Erf); ok {
-				if !f.Flag('-') {
-					for _, line := range strings.Split(e2.Error(), "\n") {
-						buf.Write(padding)
-						buf.Write(indent)
-						buf.WriteString(line)
-						buf.WriteRune('\n')
-					}
+		if idx > 0 {
+			buf.WriteRune('\n')
+		}
+		idx++
+		formatError(buf, f, err, pad, wid+branch, prec)
+		writePadding(buf, pad, wid+branch)
+		return true
+	})
+	_, _ = f.Write(buf.Bytes())
+}
+
+func formatError(buf *bytes.Buffer, f fmt.State, err error, pad byte, wid, prec int) {
+	e, ok := err.(TracedError)
+	if !ok {
+		if !f.Flag('-') {
+			writeLines(buf, err.Error(), pad, wid+prec)
+		} else {
+			writePadding(buf, pad, wid)
+			buf.WriteString("- ")
+			buf.WriteRune('\n')
+		}
+		return
+	}
+	if !f.Flag('-') {
+		writeLines(buf, e.Error(), pad, wid+prec)
+	}
+	st := e.StackTrace()
+	switch {
+	case st == nil:
+		writePadding(buf, pad, wid)
+		buf.WriteString("* stack trace not captured")
+	case st.Len() <= 0 && !st.Truncated():
+		writePadding(buf, pad, wid)
+		buf.WriteString("* stack trace empty")
+	default:
+		st.format(buf, true, f.Flag('#'), pad, wid, prec)
+	}
+	buf.WriteRune('\n')
+	if e, ok := e.(interface{ ReturnTrace() *StackTrace }); ok {
+		if rt := e.ReturnTrace(); rt != nil {
+			writePadding(buf, pad, wid)
+			buf.WriteString("< return trace")
+			buf.WriteRune('\n')
+			rt.format(buf, true, f.Flag('#'), pad, wid, prec)
+			buf.WriteRune('\n')
+		}
+	}
+	if e, ok := e.(interface{ SpawnTraces() []*StackTrace }); ok {
+		for _, st := range e.SpawnTraces() {
+			writePadding(buf, pad, wid)
+			buf.WriteString("> spawned at")
+			buf.WriteRune('\n')
+			st.format(buf, true, f.Flag('#'), pad, wid, prec)
+			buf.WriteRune('\n')
+		}
+	}
+	if f.Flag('+') {
+		if e, ok := e.(interface {
+			Code() *Code
+			Kind() *Kind
+		}); ok {
+			c, k := e.Code(), e.Kind()
+			if c != nil || k != nil {
+				writePadding(buf, pad, wid)
+				buf.WriteString("=")
+				if c != nil {
+					buf.WriteString(" code ")
+					writeInt(buf, c.Number())
+					buf.WriteRune(' ')
+					writeQuote(buf, c.Name())
 				}
-				str := fmt.Sprintf(format, e2.StackTrace())
-				if str != "" {
-					buf.WriteString(str)
-				} else {
-					buf.Write(padding)
-					buf.WriteString("* ")
+				if k != nil {
+					buf.WriteString(" kind ")
+					writeQuote(buf, k.Name())
 				}
 				buf.WriteRune('\n')
-				if f.Flag('+') {
-					tags := e2.Tags()
-					if len(tags) > 0 {
-						buf.Write(padding)
-						buf.WriteString("+ ")
-						for idx, tag := range tags {
-							if idx > 0 {
-								buf.WriteRune(' ')
-							}
-							buf.WriteString(fmt.Sprintf("%q=%q", tag, fmt.Sprintf("%v", e2.Tag(tag))))
-						}
-						buf.WriteRune('\n')
-					}
-				}
-			} else {
-				if !f.Flag('-') {
-					for _, line := range strings.Split(err.Error(), "\n") {
-						buf.Write(padding)
-						buf.Write(indent)
-						buf.WriteString(line)
-						buf.WriteRune('\n')
-					}
-				} else {
-					buf.Write(padding)
-					buf.WriteString("- ")
-					buf.WriteRune('\n')
+			}
+		}
+		tags := e.Tags()
+		if len(tags) > 0 {
+			writePadding(buf, pad, wid)
+			buf.WriteString("+ ")
+			for idx, tag := range tags {
+				if idx > 0 {
+					buf.WriteRune(' ')
 				}
+				writeQuote(buf, tag)
+				buf.WriteRune('=')
+				writeQuote(buf, fmt.Sprintf("%v", e.Tag(tag)))
 			}
-			buf.Write(padding)
-			if verb == 'X' {
-				break
+			buf.WriteRune('\n')
+		}
+		if e, ok := e.(interface{ Diagnostics() []error }); ok {
+			for _, diag := range e.Diagnostics() {
+				writePadding(buf, pad, wid)
+				buf.WriteString("! ")
+				buf.WriteString(diag.Error())
+				buf.WriteRune('\n')
 			}
 		}
-	default:
-		return
 	}
-	_, _ = f.Write(buf.Bytes())
 }
 
 // Fmt returns the format argument of the formatting functions (Newf, Errorf or Wrap) that created Erf.
@@ -234,12 +375,27 @@ func (e *Erf) Len() int {
 // 	tags are already attached
 // 	number of tags is more than args
 // 	tag already defined
+// In the lenient mode, it doesn't panic, the invalid tags are skipped and the problems are recorded as diagnostics.
+// See also SetLenient and TryAttach.
 func (e *Erf) Attach(tags ...string) *Erf {
+	l := Lenient()
+	e.diagnose(e.attach(tags, l), l)
+	return e
+}
+
+// attach attaches tags to arguments, and returns the problems.
+// If lenient is false, it returns the first problem without attaching any tag.
+func (e *Erf) attach(tags []string, lenient bool) []error {
 	if e.tags != nil || e.tagIndexes != nil {
-		panic("tags are already attached")
+		return []error{ErrTagsAlreadyAttached}
 	}
+	var diags []error
 	if len(tags) > len(e.args) {
-		panic("number of tags is more than args")
+		if !lenient {
+			return []error{ErrTooManyTags}
+		}
+		diags = append(diags, ErrTooManyTags)
+		tags = tags[:len(e.args)]
 	}
 	t := make([]string, 0, len(tags))
 	ti := make(map[string]int, len(tags))
@@ -247,15 +403,19 @@ func (e *Erf) Attach(tags ...string) *Erf {
 		if tag == "" {
 			continue
 		}
-		if _, ok := ti[tag]; ok {
-			panic("tag already defined")
+		if _, ok := ti[tag]; ok || e.hasAttr(tag) {
+			if !lenient {
+				return []error{ErrTagAlreadyDefined}
+			}
+			diags = append(diags, ErrTagAlreadyDefined)
+			continue
 		}
 		t = append(t, tag)
 		ti[tag] = index
 	}
 	e.tags = t
 	e.tagIndexes = ti
-	return e
+	return diags
 }
 
 // Attach2 is similar with Attach except that it returns the error interface instead of the Erf pointer.
@@ -263,16 +423,17 @@ func (e *Erf) Attach2(tags ...string) error {
 	return e.Attach(tags...)
 }
 
-// Tag returns an argument value on the given tag. It returns nil if tag is not found.
+// Tag returns an argument value or an attribute value on the given tag. It returns nil if tag is not found.
 func (e *Erf) Tag(tag string) interface{} {
 	index := e.TagIndex(tag)
 	if index < 0 {
-		return nil
+		return e.Attr(tag)
 	}
 	return e.args[index]
 }
 
-// TagIndex returns index of an argument on the given tag. It returns -1 if tag is not found.
+// TagIndex returns index of an argument on the given tag.
+// It returns -1 if tag is not found or tag is the key of an attribute.
 func (e *Erf) TagIndex(tag string) int {
 	index := -1
 	if idx, ok := e.tagIndexes[tag]; ok {
@@ -284,19 +445,23 @@ func (e *Erf) TagIndex(tag string) int {
 	return index
 }
 
-// Tags returns all tags sequentially. It returns nil if tags are not attached.
+// Tags returns all tags sequentially, the tags attached to arguments are followed by the keys of attributes.
+// It returns nil if tags are not attached and there is no attribute.
 func (e *Erf) Tags() []string {
-	if e.tags == nil {
+	if e.tags == nil && e.attrs == nil {
 		return nil
 	}
-	result := make([]string, len(e.tags))
-	copy(result, e.tags)
+	result := make([]string, 0, len(e.tags)+len(e.attrs))
+	result = append(result, e.tags...)
+	for _, attr := range e.attrs {
+		result = append(result, attr.Key)
+	}
 	return result
 }
 
-// TagsLen returns the length of all tags.
+// TagsLen returns the length of all tags including the keys of attributes.
 func (e *Erf) TagsLen() int {
-	return len(e.tags)
+	return len(e.tags) + len(e.attrs)
 }
 
 // PC returns all program counters.
@@ -311,51 +476,151 @@ func (e *Erf) PCLen() int {
 	return len(e.pc)
 }
 
-// StackTrace returns a StackTrace of Erf.
+// StackTrace returns a StackTrace of Erf. It returns nil if the stack wasn't captured.
+// The StackTrace is resolved once and cached on Erf, so it shouldn't be modified.
+// If Erf was decoded by UnmarshalJSON, it returns the decoded StackTrace.
 func (e *Erf) StackTrace() *StackTrace {
-	return NewStackTrace(e.pc...)
+	if !e.captured {
+		return nil
+	}
+	if e.syntheticST != nil {
+		return e.syntheticST
+	}
+	if t := (*StackTrace)(atomic.LoadPointer(&e.st)); t != nil {
+		return t
+	}
+	t := NewStackTrace(e.pc...)
+	t.truncated = e.truncated
+	if !atomic.CompareAndSwapPointer(&e.st, nil, unsafe.Pointer(t)) {
+		return (*StackTrace)(atomic.LoadPointer(&e.st))
+	}
+	return t
+}
+
+// Captured returns true if the stack was captured while creating Erf, otherwise false.
+func (e *Erf) Captured() bool {
+	return e.captured
+}
+
+func (e *Erf) initialize(skip int, capture Capture) {
+	if capture == CaptureNone {
+		return
+	}
+	size := DefaultPCSize
+	if capture > 0 && int(capture) < size {
+		size = int(capture)
+	}
+	e.pc, e.truncated = capturePC(size, skip)
+	e.captured = true
+}
+
+// textErf is used to allocate Erf and its underlying error at once.
+type textErf struct {
+	e    Erf
+	text textError
+}
+
+// textError is the underlying error of the Erf objects created with text.
+type textError struct {
+	s string
 }
 
-func (e *Erf) initialize(skip int) {
-	e.pc = PC(DefaultPCSize, skip)
+func (e *textError) Error() string {
+	return e.s
+}
+
+func newText(text string) *Erf {
+	t := &textErf{
+		text: textError{
+			s: text,
+		},
+	}
+	t.e.err = &t.text
+	return &t.e
+}
+
+// wrapErf is used to allocate Erf, its underlying error and its argument at once.
+type wrapErf struct {
+	e    Erf
+	wrap wrapError
+	args [1]interface{}
+}
+
+// wrapError is the underlying error of the Erf objects created with wrapping functions.
+// It is same with the error that returned by fmt.Errorf("%w", err).
+type wrapError struct {
+	msg string
+	err error
+}
+
+func (e *wrapError) Error() string {
+	return e.msg
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+func newWrap(err error) *Erf {
+	t := &wrapErf{
+		wrap: wrapError{
+			msg: err.Error(),
+			err: err,
+		},
+	}
+	t.args[0] = err
+	t.e.err = &t.wrap
+	t.e.format = "%w"
+	t.e.args = t.args[:]
+	return &t.e
 }
 
 // New creates a new Erf object with the given text.
 func New(text string) *Erf {
-	e := &Erf{
-		err: errors.New(text),
-	}
-	e.initialize(4)
+	e := newText(text)
+	e.initialize(4, DefaultCapture())
 	return e
 }
 
 func newf(format string, args ...interface{}) *Erf {
 	e := &Erf{
-		err:    fmt.Errorf(format, args...),
 		format: format,
 		args:   make([]interface{}, 0, len(args)),
 	}
-	for _, arg := range args {
+	var diags []error
+	fargs := args
+	for index, arg := range args {
 		if arg == nil {
-			panic("arg is nil")
+			if len(diags) <= 0 {
+				fargs = make([]interface{}, len(args))
+				copy(fargs, args)
+			}
+			diags = append(diags, ErrArgIsNil)
+			fargs[index] = nilArg{}
 		}
 		e.args = append(e.args, arg)
 	}
+	e.diagnose(diags, Lenient())
+	if len(diags) > 0 {
+		format = unwrapNilArgs(format, fargs)
+	}
+	e.err = fmt.Errorf(format, fargs...)
 	return e
 }
 
 // Newf creates a new Erf object with the given format and args.
-// It panics if an any arg is nil.
+// It panics if an any arg is nil. In the lenient mode, the nil args are rendered as "<nil>" and recorded as
+// diagnostics. See also SetLenient.
 func Newf(format string, args ...interface{}) *Erf {
 	e := newf(format, args...)
-	e.initialize(4)
+	e.initialize(4, DefaultCapture())
 	return e
 }
 
 // Errorf is similar with Newf except that it returns the error interface instead of the Erf pointer.
 func Errorf(format string, a ...interface{}) error {
 	e := newf(format, a...)
-	e.initialize(4)
+	e.initialize(4, DefaultCapture())
 	return e
 }
 
@@ -365,8 +630,8 @@ func Wrap(err error) error {
 	if err == nil {
 		return nil
 	}
-	e := newf("%w", err)
-	e.initialize(4)
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
 	return e
 }
 
@@ -380,7 +645,57 @@ func Wrapp(perr *error) {
 	if err == nil {
 		return
 	}
-	e := newf("%w", err)
-	e.initialize(4)
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
+	*perr = e
+}
+
+// NewSkip is similar with New except that it skips the given number of stack frames above the caller.
+// If skip is 0, NewSkip is same with New.
+func NewSkip(skip int, text string) *Erf {
+	e := newText(text)
+	e.initialize(4+skip, DefaultCapture())
+	return e
+}
+
+// NewfSkip is similar with Newf except that it skips the given number of stack frames above the caller.
+// If skip is 0, NewfSkip is same with Newf.
+func NewfSkip(skip int, format string, args ...interface{}) *Erf {
+	e := newf(format, args...)
+	e.initialize(4+skip, DefaultCapture())
+	return e
+}
+
+// ErrorfSkip is similar with Errorf except that it skips the given number of stack frames above the caller.
+// If skip is 0, ErrorfSkip is same with Errorf.
+func ErrorfSkip(skip int, format string, a ...interface{}) error {
+	e := newf(format, a...)
+	e.initialize(4+skip, DefaultCapture())
+	return e
+}
+
+// WrapSkip is similar with Wrap except that it skips the given number of stack frames above the caller.
+// If skip is 0, WrapSkip is same with Wrap.
+func WrapSkip(skip int, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4+skip, DefaultCapture())
+	return e
+}
+
+// WrappSkip is similar with Wrapp except that it skips the given number of stack frames above the caller.
+// If skip is 0, WrappSkip is same with Wrapp.
+func WrappSkip(skip int, perr *error) {
+	if perr == nil {
+		return
+	}
+	err := *perr
+	if err == nil {
+		return
+	}
+	e := newWrap(err)
+	e.initialize(4+skip, DefaultCapture())
 	*perr = e
 }
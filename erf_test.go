@@ -1,7 +1,12 @@
 package erf_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"testing"
 
 	"github.com/goinsane/erf"
 )
@@ -13,25 +18,25 @@ func ExampleErf() {
 	fmt.Println("just show the first error message without padding and indent.")
 	fmt.Printf("%v\n\n", err)
 
-	fmt.Println("list all error messages by using indent and show StackTrace of errors by using format '%+s'.")
+	fmt.Printf("list all error messages by using indent and show StackTrace of errors by using format '%%+s'.\n")
 	fmt.Printf("%x\n\n", err)
 
 	fmt.Println("list all error messages by using indent and show StackTrace of errors by using format '% s'.")
 	fmt.Printf("% x\n\n", err)
 
-	fmt.Println("list all error messages by using indent and show StackTrace of errors by using format '%#s'.")
+	fmt.Printf("list all error messages by using indent and show StackTrace of errors by using format '%%#s'.\n")
 	fmt.Printf("%#x\n\n", err)
 
 	fmt.Println("list all error messages by using indent and show StackTrace of errors by using format '% #s'.")
 	fmt.Printf("% #x\n\n", err)
 
-	fmt.Println("show the first error message by using indent and show the StackTrace of error by using format '%+s'.")
+	fmt.Printf("show the first error message by using indent and show the StackTrace of error by using format '%%+s'.\n")
 	fmt.Printf("%X\n\n", err)
 
 	fmt.Println("show the first error message by using indent and show the StackTrace of error by using format '% s'.")
 	fmt.Printf("% X\n\n", err)
 
-	fmt.Println("show the first error message by using indent and show the StackTrace of error by using format '%#s'.")
+	fmt.Printf("show the first error message by using indent and show the StackTrace of error by using format '%%#s'.\n")
 	fmt.Printf("%#X\n\n", err)
 
 	fmt.Println("show the first error message by using indent and show the StackTrace of error by using format '% #s'.")
@@ -61,3 +66,181 @@ func ExampleErf() {
 	fmt.Println("padding 0 by default, indent 3. padding char ' '.")
 	fmt.Printf("% .3x\n\n", err)
 }
+
+func ExampleErf_Format() {
+	e1 := erf.CaptureNone.New("user not found")
+	e2 := fmt.Errorf("worker failed: %w, %w", errors.New("disk full"), erf.CaptureNone.New("timeout"))
+	err := erf.CaptureNone.Errorf("request failed: %w; %w", e1, e2)
+
+	fmt.Println("list all error messages by using indent and show the branches of the tree.")
+	printLines(fmt.Sprintf("%x", err))
+
+	fmt.Println("show the first error message by using indent.")
+	printLines(fmt.Sprintf("%X", err))
+
+	// Output:
+	// list all error messages by using indent and show the branches of the tree.
+	// 	request failed: user not found; worker failed: disk full, timeout
+	// * stack trace not captured
+	//
+	// 		user not found
+	// 	* stack trace not captured
+	//
+	// 		worker failed: disk full, timeout
+	//
+	// 			disk full
+	//
+	// 			timeout
+	// 		* stack trace not captured
+	//
+	// show the first error message by using indent.
+	// 	request failed: user not found; worker failed: disk full, timeout
+	// * stack trace not captured
+}
+
+// printLines prints the lines of s by trimming the trailing padding chars of the lines, and by masking
+// the entry addresses, the line numbers and the offsets of the stack traces.
+func printLines(s string) {
+	s = regexp.MustCompile(`\(0x[0-9a-f]+\)`).ReplaceAllString(s, "(0x?)")
+	s = regexp.MustCompile(`:[0-9]+ \+0x[0-9a-f]+`).ReplaceAllString(s, ":? +0x?")
+	for _, line := range strings.Split(s, "\n") {
+		fmt.Println(strings.TrimRight(line, "\t "))
+	}
+}
+
+type exampleInvalidArgumentError struct{ *erf.Erf }
+
+func newExampleInvalidArgumentError(name string, err error) error {
+	e := &exampleInvalidArgumentError{erf.CaptureCaller.Newf("invalid argument %q: %w", name, err)}
+	e.Attach("name")
+	return e
+}
+
+func ExampleTracedError() {
+	err := erf.CaptureNone.Errorf("request failed: %w",
+		newExampleInvalidArgumentError("x", erf.CaptureNone.New("value below zero")))
+
+	fmt.Println("the type that embeds Erf is shown with its StackTrace and tags.")
+	printLines(fmt.Sprintf("%+#x", err))
+
+	data, _ := json.Marshal(err)
+	var e2 erf.Erf
+	_ = json.Unmarshal(data, &e2)
+
+	fmt.Println("the type that embeds Erf is encoded with its StackTrace and tags.")
+	printLines(fmt.Sprintf("%+#x", &e2))
+
+	// Output:
+	// the type that embeds Erf is shown with its StackTrace and tags.
+	// 	request failed: invalid argument "x": value below zero
+	// * stack trace not captured
+	//
+	// 	invalid argument "x": value below zero
+	// github.com/goinsane/erf_test.newExampleInvalidArgumentError(0x?)
+	// 	erf_test.go:? +0x?
+	// * stack trace truncated
+	// + "name"="x"
+	//
+	// 	value below zero
+	// * stack trace not captured
+	//
+	// the type that embeds Erf is encoded with its StackTrace and tags.
+	// 	request failed: invalid argument "x": value below zero
+	// * stack trace not captured
+	//
+	// 	invalid argument "x": value below zero
+	// github.com/goinsane/erf_test.newExampleInvalidArgumentError(0x?)
+	// 	erf_test.go:? +0x?
+	// * stack trace truncated
+	// + "name"="x"
+	//
+	// 	value below zero
+	// * stack trace not captured
+}
+
+func ExampleWalk() {
+	e1 := erf.New("first example erf error")
+	e2 := erf.New("second example erf error")
+	err := erf.Errorf("we have example errors: %w, %w", e1, erf.Wrap(e2))
+
+	erf.Walk(err, func(err error, depth int) bool {
+		fmt.Printf("%d %v\n", depth, err)
+		return true
+	})
+
+	// Output:
+	// 0 we have example errors: first example erf error, second example erf error
+	// 1 first example erf error
+	// 1 second example erf error
+	// 2 second example erf error
+}
+
+func newExampleError(text string) *erf.Erf {
+	erf.Helper()
+	return erf.New(text)
+}
+
+func newExampleSkipError(text string) *erf.Erf {
+	return erf.NewSkip(1, text)
+}
+
+func ExampleHelper() {
+	e1 := newExampleError("an example erf error created by helper")
+	e2 := newExampleSkipError("an example erf error created by skipping")
+
+	fmt.Println(e1.StackTrace().Caller(0).Function)
+	fmt.Println(e2.StackTrace().Caller(0).Function)
+
+	// Output:
+	// github.com/goinsane/erf_test.ExampleHelper
+	// github.com/goinsane/erf_test.ExampleHelper
+}
+
+func newExampleSkipErrors() []error {
+	err := errors.New("an example error")
+	perr := err
+	erf.WrappSkip(1, &perr)
+	return []error{
+		erf.NewSkip(1, "an example erf error"),
+		erf.NewfSkip(1, "an example erf error on %q", "foo"),
+		erf.ErrorfSkip(1, "an example erf error: %w", err),
+		erf.WrapSkip(1, err),
+		perr,
+	}
+}
+
+func ExampleWrapSkip() {
+	for _, err := range newExampleSkipErrors() {
+		fmt.Println(err.(*erf.Erf).StackTrace().Caller(0).Function)
+	}
+
+	// Output:
+	// github.com/goinsane/erf_test.ExampleWrapSkip
+	// github.com/goinsane/erf_test.ExampleWrapSkip
+	// github.com/goinsane/erf_test.ExampleWrapSkip
+	// github.com/goinsane/erf_test.ExampleWrapSkip
+	// github.com/goinsane/erf_test.ExampleWrapSkip
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = erf.New("an example erf error")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	err := errors.New("an example error")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = erf.Wrap(err)
+	}
+}
+
+func BenchmarkErf_Format(b *testing.B) {
+	err := erf.Wrap(erf.Newf("an example erf error on %q", "foo").Attach("name"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+x", err)
+	}
+}
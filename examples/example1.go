@@ -10,18 +10,21 @@ import (
 )
 
 var (
-	ErrValueBelowZero = erf.New("value below zero")
+	ErrValueBelowZero = erf.NewSentinel("value below zero")
 )
 
 type InvalidArgumentError struct{ *erf.Erf }
 
 func NewInvalidArgumentError(name string, err error) error {
-	return (&InvalidArgumentError{erf.Newf("invalid argument %q: %w", name, err)}).Attach("name")
+	erf.Helper()
+	e := &InvalidArgumentError{erf.Newf("invalid argument %q: %w", name, err)}
+	e.Attach("name")
+	return e
 }
 
 func Foo(x int) error {
 	if x < 0 {
-		return NewInvalidArgumentError("x", ErrValueBelowZero)
+		return NewInvalidArgumentError("x", ErrValueBelowZero.Here())
 	}
 	return nil
 }
@@ -35,7 +38,7 @@ func Bar(y int) error {
 
 func Baz(z int) error {
 	if z < 0 {
-		return erf.Wrap(ErrValueBelowZero)
+		return erf.Wrap(ErrValueBelowZero.Here())
 	}
 	return nil
 }
@@ -82,6 +85,12 @@ func main() {
 		fmt.Printf("%2.1x\n", err)
 	}
 
+	fmt.Println("#### Foo: show wrapped error with stack trace and tags")
+	if err := Foo(-12); err != nil {
+		err = erf.Wrap(err)
+		fmt.Printf("%+x\n", err)
+	}
+
 	fmt.Println("#### Baz: show with stack trace")
 	if err := Baz(-9); err != nil {
 		fmt.Printf("%x\n", err)
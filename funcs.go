@@ -2,11 +2,45 @@ package erf
 
 import (
 	"runtime"
+	"sync/atomic"
 )
 
 // PC returns program counters by using runtime.Callers.
+// The returned slice is right-sized, it doesn't keep the buffer of the given size.
 func PC(size, skip int) []uintptr {
-	pc := make([]uintptr, size)
-	pc = pc[:runtime.Callers(skip, pc)]
+	p := pcPool.Get().(*[]uintptr)
+	defer pcPool.Put(p)
+	if len(*p) < size {
+		*p = make([]uintptr, size)
+	}
+	buf := (*p)[:runtime.Callers(skip, (*p)[:size])]
+	pc := make([]uintptr, len(buf))
+	copy(pc, buf)
 	return pc
 }
+
+// Walk walks the error tree of err in depth-first order by using Unwrap methods, and calls fn for each error
+// with its depth. The depth of err is 0. If fn returns false, Walk doesn't walk the errors wrapped by that error.
+func Walk(err error, fn func(err error, depth int) bool) {
+	if err == nil {
+		return
+	}
+	walkErrors(err, 0, 0, func(err error, depth, branch int) bool {
+		return fn(err, depth)
+	})
+}
+
+// Helper marks the calling function as a helper function, similar with testing.TB.Helper.
+// The frames of helper functions on the top of the stack are dropped from the stack traces that captured by
+// the functions creating Erf, so the first frame of the stack trace is the caller of the helper function.
+// Helper can be called simultaneously from multiple goroutines.
+func Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) < 1 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+	if _, loaded := helpers.LoadOrStore(frame.Function, struct{}{}); !loaded {
+		atomic.AddInt32(&helpersLen, 1)
+	}
+}
@@ -0,0 +1,37 @@
+package erf
+
+// TagAs finds the nearest value of the given tag that has the type T in the error tree of err, and reports whether
+// the value is found. The errors are visited in the same order with LookupTag.
+func TagAs[T any](err error, tag string) (value T, ok bool) {
+	Walk(err, func(err error, depth int) bool {
+		if ok {
+			return false
+		}
+		var v interface{}
+		if v, ok = lookupTag(err, tag); ok {
+			value, ok = v.(T)
+		}
+		return !ok
+	})
+	return value, ok
+}
+
+// ArgsOfType returns all arguments that have the type T in the error tree of err.
+// The errors are visited in the same order with Walk, and the arguments of each error are sorted by their indexes.
+// It returns nil if there is no argument that has the type T.
+func ArgsOfType[T any](err error) []T {
+	var result []T
+	Walk(err, func(err error, depth int) bool {
+		e, ok := err.(interface{ Args() []interface{} })
+		if !ok {
+			return true
+		}
+		for _, arg := range e.Args() {
+			if v, ok := arg.(T); ok {
+				result = append(result, v)
+			}
+		}
+		return true
+	})
+	return result
+}
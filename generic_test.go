@@ -0,0 +1,33 @@
+package erf_test
+
+import (
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleTagAs() {
+	e := erf.Newf("invalid argument %q: value %d below zero", "x", -1).Attach("name", "value")
+	err := erf.Wrap(fmt.Errorf("we have an example error: %w", e))
+
+	fmt.Println(erf.TagAs[string](err, "name"))
+	fmt.Println(erf.TagAs[int](err, "value"))
+	fmt.Println(erf.TagAs[int](err, "name"))
+
+	// Output:
+	// x true
+	// -1 true
+	// 0 false
+}
+
+func ExampleArgsOfType() {
+	e := erf.Newf("invalid argument %q: value %d below zero", "x", -1)
+	err := erf.Newf("argument %q of %q: %w", "x", "foo", e)
+
+	fmt.Println(erf.ArgsOfType[string](err))
+	fmt.Println(erf.ArgsOfType[int](err))
+
+	// Output:
+	// [x foo x]
+	// [-1]
+}
@@ -1,3 +1,3 @@
 module github.com/goinsane/erf
 
-go 1.13
+go 1.20
@@ -0,0 +1,60 @@
+package erf
+
+import (
+	"log"
+)
+
+// Reporter is the interface that receives the results of the goroutines started by Go.
+type Reporter interface {
+	Report(err error)
+}
+
+// ReporterFunc is an adapter to allow the use of ordinary functions as Reporter.
+type ReporterFunc func(err error)
+
+// Report is implementation of Reporter.
+func (f ReporterFunc) Report(err error) {
+	f(err)
+}
+
+// ChanReporter returns a Reporter that sends the results to the given channel.
+func ChanReporter(ch chan<- error) Reporter {
+	return ReporterFunc(func(err error) {
+		ch <- err
+	})
+}
+
+// LogReporter returns a Reporter that writes the non-nil results to the given logger by using format '%+x'.
+// If logger is nil, it uses the standard logger.
+func LogReporter(logger *log.Logger) Reporter {
+	return ReporterFunc(func(err error) {
+		if err == nil {
+			return
+		}
+		if logger == nil {
+			log.Printf("%+x", err)
+			return
+		}
+		logger.Printf("%+x", err)
+	})
+}
+
+// Go calls fn in a new goroutine, and reports the result of fn to r.
+// If fn panics, the panic is recovered and reported as an Erf object created by NewPanic.
+// Every result is reported, including nil, so the results can be counted.
+// If r is nil, the non-nil results are written to the standard logger like LogReporter.
+// Go captures the stack of the caller as a spawn point, and links it to the non-nil result like Spawned.
+func Go(r Reporter, fn func() error) {
+	if r == nil {
+		r = LogReporter(nil)
+	}
+	s := newSpawn(nil, 4)
+	go func() {
+		r.Report(linkSpawn(call(fn), s))
+	}()
+}
+
+func call(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
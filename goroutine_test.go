@@ -0,0 +1,28 @@
+package erf_test
+
+import (
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleGo() {
+	ch := make(chan error, 2)
+	r := erf.ChanReporter(ch)
+
+	erf.Go(r, func() error {
+		return erf.New("an example erf error")
+	})
+	erf.Go(r, func() error {
+		panic("an example panic")
+	})
+
+	msgs := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		msgs[fmt.Sprintf("%v", <-ch)] = true
+	}
+	fmt.Println(msgs["an example erf error"], msgs["panic: an example panic"])
+
+	// Output:
+	// true true
+}
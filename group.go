@@ -0,0 +1,99 @@
+package erf
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a collection of goroutines working on subtasks, similar with errgroup.Group.
+// Unlike errgroup.Group, Group collects all errors returned by the goroutines and the panics of the goroutines
+// as Erf objects.
+// A zero Group is valid, has no limit on the number of active goroutines and doesn't cancel on error.
+type Group struct {
+	cancel func()
+	spawn  *spawn
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	mu     sync.Mutex
+	errs   []error
+}
+
+// NewGroup returns a new Group and an associated context derived from ctx.
+// The derived context is canceled the first time a function passed to Go returns a non-nil error or panics,
+// or the first time Wait returns, whichever occurs first.
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		cancel: cancel,
+		spawn:  spawnFromContext(ctx),
+	}, ctx
+}
+
+// SetLimit limits the number of active goroutines in Group to at most n. A negative value indicates no limit.
+// Any subsequent call to Go blocks until it can add an active goroutine without exceeding the limit.
+// It panics if the limit is modified while any goroutine in Group is active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic("limit modified while goroutines are active")
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls fn in a new goroutine. It blocks until the new goroutine can be added without exceeding the limit.
+// If fn returns a non-nil error or panics, the error is collected and the context of Group is canceled.
+// The panics are converted to Erf objects by using NewPanic. Go captures the stack of the caller as a spawn point,
+// and links it to the collected error like Spawned.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	s := newSpawn(g.spawn, 4)
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := call(fn); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, linkSpawn(err, s))
+			g.mu.Unlock()
+			if g.cancel != nil {
+				g.cancel()
+			}
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+// Wait blocks until all function calls from the Go method have returned, then returns all collected errors as
+// an Erf object that wraps them as a MultiError. It returns nil if there is no error.
+// The returned error matches each collected error by using errors.Is and errors.As, and shows StackTrace's of
+// all collected errors by using '%x'.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) <= 0 {
+		return nil
+	}
+	errs := make([]error, len(g.errs))
+	copy(errs, g.errs)
+	e := &Erf{
+		err: &MultiError{
+			errs: errs,
+		},
+	}
+	e.initialize(4, DefaultCapture())
+	return e
+}
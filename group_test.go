@@ -0,0 +1,46 @@
+package erf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleGroup() {
+	errFoo := erf.New("an example erf error")
+
+	g, ctx := erf.NewGroup(context.Background())
+	g.SetLimit(2)
+	g.Go(func() error {
+		return errFoo
+	})
+	g.Go(func() error {
+		panic("an example panic")
+	})
+	var ctxErr error
+	g.Go(func() error {
+		<-ctx.Done()
+		ctxErr = ctx.Err()
+		return nil
+	})
+	err := g.Wait()
+
+	fmt.Println("the returned error matches the collected errors.")
+	fmt.Println(errors.Is(err, errFoo))
+
+	fmt.Println("the number of the collected errors.")
+	fmt.Println(len(err.(*erf.Erf).UnwrapMulti()))
+
+	fmt.Println("the context was canceled after the first error, before Wait returns.")
+	fmt.Println(ctxErr)
+
+	// Output:
+	// the returned error matches the collected errors.
+	// true
+	// the number of the collected errors.
+	// 2
+	// the context was canceled after the first error, before Wait returns.
+	// context canceled
+}
@@ -0,0 +1,182 @@
+package httperf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/goinsane/erf"
+)
+
+// MaxBodySize is the maximum number of bytes that read from the response bodies by DecodeResponse.
+const MaxBodySize = 1 << 20
+
+// StatusTag is the tag of the HTTP status code in the Erf objects returned by DecodeResponse.
+const StatusTag = "status"
+
+// RemoteError is the error that decoded from the error response of a remote peer.
+// If the peer sent the JSON encoded Erf, RemoteError wraps the decoded Erf that has the remote message chain,
+// the remote stack traces, the code, the kind and the tags.
+type RemoteError struct {
+	problem *Problem
+	err     error
+}
+
+// ParseError parses the given error response body as the problem details or the JSON encoded Erf,
+// and returns a RemoteError. If the body isn't parsable, the body is used as the detail of the problem details.
+// The status code is used if the problem details don't have it.
+func ParseError(status int, contentType string, body []byte) *RemoteError {
+	e := &RemoteError{
+		problem: &Problem{},
+	}
+	var remote *erf.Erf
+	parsed := false
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case ContentType:
+		if json.Unmarshal(body, e.problem) != nil {
+			e.problem = &Problem{}
+			break
+		}
+		parsed = true
+		if e.problem.Erf != nil {
+			remote = decodeErf(e.problem.Erf)
+		}
+	case "application/json":
+		if remote = decodeErf(body); remote != nil {
+			parsed = true
+			e.problem.Erf = body
+		}
+	}
+	if remote != nil {
+		e.err = remote
+		if e.problem.Detail == "" {
+			e.problem.Detail = remote.Error()
+		}
+		if code := erf.CodeOf(remote); code != nil && e.problem.Code == nil {
+			e.problem.Code = &ProblemCode{
+				Number: code.Number(),
+				Name:   code.Name(),
+			}
+		}
+		if kind := erf.KindOf(remote); kind != nil && e.problem.Kind == "" {
+			e.problem.Kind = kind.Name()
+		}
+	}
+	if e.problem.Status == 0 {
+		e.problem.Status = status
+	}
+	if e.problem.Title == "" {
+		e.problem.Title = http.StatusText(e.problem.Status)
+	}
+	if !parsed {
+		e.problem.Detail = strings.TrimSpace(string(bytes.ToValidUTF8(body, []byte("\uFFFD"))))
+	}
+	return e
+}
+
+// DecodeResponse decodes the given response as an error if its status code isn't 2xx or 3xx, and returns an Erf
+// object that wraps the RemoteError and captures the stack of the caller. So '%x' shows both the local and
+// the remote part of the failure. The status code is the argument tagged with StatusTag.
+// DecodeResponse reads the body up to MaxBodySize bytes, but doesn't close it.
+// It returns nil if the status code is 2xx or 3xx.
+func DecodeResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	}
+	remote := ParseError(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	return erf.NewfSkip(1, "remote error %d: %w", resp.StatusCode, remote).Attach(StatusTag)
+}
+
+func decodeErf(data []byte) *erf.Erf {
+	var e erf.Erf
+	if json.Unmarshal(data, &e) != nil {
+		return nil
+	}
+	return &e
+}
+
+// Error is implementation of error. It returns the detail of the problem details, or the title if there is no detail.
+func (e *RemoteError) Error() string {
+	if e.problem.Detail != "" {
+		return e.problem.Detail
+	}
+	return e.problem.Title
+}
+
+// Unwrap returns the decoded Erf if the peer sent the JSON encoded Erf, otherwise nil.
+func (e *RemoteError) Unwrap() error {
+	return e.err
+}
+
+// Problem returns a copy of the problem details.
+func (e *RemoteError) Problem() *Problem {
+	p := *e.problem
+	return &p
+}
+
+// StatusCode returns the HTTP status code.
+func (e *RemoteError) StatusCode() int {
+	return e.problem.Status
+}
+
+// Code returns the registered Code that has the same number and name with the remote code.
+// It returns nil if there is no remote code or it isn't registered.
+func (e *RemoteError) Code() *erf.Code {
+	if e.problem.Code == nil {
+		return nil
+	}
+	if c := erf.LookupCode(e.problem.Code.Number); c != nil && c.Name() == e.problem.Code.Name {
+		return c
+	}
+	return nil
+}
+
+// Kind returns the registered Kind that has the same name with the remote kind.
+// It returns nil if there is no remote kind or it isn't registered.
+func (e *RemoteError) Kind() *erf.Kind {
+	if e.problem.Kind == "" {
+		return nil
+	}
+	return erf.LookupKind(e.problem.Kind)
+}
+
+// Is reports whether target is the Code of RemoteError, the Kind of RemoteError or an ancestor of the Kind.
+// It is used by errors.Is.
+func (e *RemoteError) Is(target error) bool {
+	switch t := target.(type) {
+	case *erf.Code:
+		c := e.Code()
+		return t != nil && c == t
+	case *erf.Kind:
+		k := e.Kind()
+		return t != nil && k != nil && k.Is(t)
+	}
+	return false
+}
+
+// Tags returns the names of the public tags in the problem details sequentially.
+func (e *RemoteError) Tags() []string {
+	if e.problem.Tags == nil {
+		return nil
+	}
+	result := make([]string, 0, len(e.problem.Tags))
+	for tag := range e.problem.Tags {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Tag returns the value of the public tag in the problem details. It returns nil if tag is not found.
+func (e *RemoteError) Tag(tag string) interface{} {
+	return e.problem.Tags[tag]
+}
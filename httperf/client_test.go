@@ -0,0 +1,87 @@
+package httperf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goinsane/erf"
+	"github.com/goinsane/erf/httperf"
+)
+
+func ExampleDecodeResponse() {
+	cfg := &httperf.Config{
+		Debug:      true,
+		Logger:     log.New(io.Discard, "", 0),
+		PublicTags: []string{"name"},
+	}
+	srv := httptest.NewServer(cfg.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		e := erf.Newf("user %q not found", "alice").Attach("name").WithKind(erf.KindNotFound)
+		return erf.Wrap(e)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+
+	err = httperf.DecodeResponse(resp)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, erf.KindNotFound))
+	fmt.Println(erf.LookupTag(err, "name"))
+	fmt.Println(erf.LookupTag(err, httperf.StatusTag))
+
+	var remoteErr *httperf.RemoteError
+	fmt.Println(errors.As(err, &remoteErr), remoteErr.StatusCode())
+
+	// Output:
+	// remote error 404: user "alice" not found
+	// true
+	// alice true
+	// 404 true
+	// true 404
+}
+
+func ExampleParseError() {
+	err := httperf.ParseError(http.StatusBadGateway, "text/plain", []byte("bad gateway\n"))
+
+	fmt.Println(err)
+	fmt.Println(err.Problem().Title)
+
+	// Output:
+	// bad gateway
+	// Bad Gateway
+}
+
+func ExampleConfig_NewProblem() {
+	cfg := &httperf.Config{
+		Chain: true,
+	}
+	e := erf.Newf("user %q not found", "alice").Attach("name").WithKind(erf.KindNotFound)
+	err := erf.Wrap(e)
+
+	p := cfg.NewProblem(httptest.NewRequest(http.MethodGet, "/users/alice", nil), err)
+	fmt.Printf("%s\n", p.Erf)
+
+	data, _ := json.Marshal(p)
+	err2 := httperf.ParseError(p.Status, httperf.ContentType, data)
+	fmt.Println(errors.Is(err2, erf.KindNotFound))
+	fmt.Println(erf.LookupTag(err2, "name"))
+	fmt.Println(err2.Unwrap().(*erf.Erf).StackTrace() == nil)
+
+	p = cfg.NewProblem(nil, erf.New("an example server error"))
+	fmt.Println(p.Erf == nil)
+
+	// Output:
+	// {"version":2,"errors":[{"args":["user \"alice\" not found"],"erf":true,"fmt":"%w","message":"user \"alice\" not found","wrapped":[1]},{"args":["alice"],"erf":true,"fmt":"user %q not found","kind":"not_found","message":"user \"alice\" not found","tags":[{"name":"name","index":0}]}]}
+	// true
+	// alice true
+	// true
+	// true
+}
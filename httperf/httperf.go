@@ -0,0 +1,289 @@
+// Package httperf provides HTTP error handling for Erf.
+// It maps the errors to HTTP status codes by using the kinds of Erf, and renders them as RFC 7807 problem details.
+package httperf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/goinsane/erf"
+)
+
+// ContentType is the media type of the problem details.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard HTTP status code for the requests canceled by the clients.
+const StatusClientClosedRequest = 499
+
+// Problem is the problem details defined in RFC 7807.
+// The fields Code, Kind, Tags and Erf are the extension members of the problem details.
+type Problem struct {
+	Type     string                 `json:"type,omitempty"`
+	Title    string                 `json:"title,omitempty"`
+	Status   int                    `json:"status,omitempty"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     *ProblemCode           `json:"code,omitempty"`
+	Kind     string                 `json:"kind,omitempty"`
+	Tags     map[string]interface{} `json:"tags,omitempty"`
+	Erf      json.RawMessage        `json:"erf,omitempty"`
+}
+
+// ProblemCode is the Code of Erf in the problem details.
+type ProblemCode struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+}
+
+// Config is the configuration of the HTTP error handling.
+type Config struct {
+	// Debug enables showing the error messages of the server errors and the JSON encoded Erf objects with
+	// the stack traces in the problem details. It must not be enabled in production.
+	Debug bool
+
+	// Chain enables showing the JSON encoded Erf objects without the stack traces in the problem details of
+	// the client errors, so the peers that use ParseError or DecodeResponse get the error chains with the codes,
+	// the kinds and the tags of each error. It is ignored for the server errors unless Debug is enabled.
+	Chain bool
+
+	// Logger is the logger for the errors. If Logger is nil, the standard logger is used.
+	Logger *log.Logger
+
+	// LogClientErrors enables logging the client errors that have status codes 4xx. The server errors are always logged.
+	LogClientErrors bool
+
+	// PublicTags is the list of the tags that are shown in the problem details. The values of the tags are found in
+	// the error tree by using erf.LookupTag.
+	PublicTags []string
+
+	// StatusFunc returns the HTTP status code of the given error. If StatusFunc is nil, StatusOf is used.
+	StatusFunc func(err error) int
+}
+
+// DefaultConfig is the Config that used by the functions Handler, HandlerFunc and WriteError.
+var DefaultConfig = &Config{}
+
+var statuses = struct {
+	mu sync.RWMutex
+	m  map[*erf.Kind]int
+}{
+	m: map[*erf.Kind]int{
+		erf.KindInternal:           http.StatusInternalServerError,
+		erf.KindInvalidArgument:    http.StatusBadRequest,
+		erf.KindNotFound:           http.StatusNotFound,
+		erf.KindAlreadyExists:      http.StatusConflict,
+		erf.KindPermissionDenied:   http.StatusForbidden,
+		erf.KindUnauthenticated:    http.StatusUnauthorized,
+		erf.KindFailedPrecondition: http.StatusBadRequest,
+		erf.KindResourceExhausted:  http.StatusTooManyRequests,
+		erf.KindCanceled:           StatusClientClosedRequest,
+		erf.KindDeadlineExceeded:   http.StatusGatewayTimeout,
+		erf.KindUnavailable:        http.StatusServiceUnavailable,
+		erf.KindUnimplemented:      http.StatusNotImplemented,
+	},
+}
+
+// RegisterStatus registers the HTTP status code of the given Kind, the descendants of the Kind that don't have
+// any registered status code use it too. The predefined kinds of erf are already registered.
+func RegisterStatus(kind *erf.Kind, status int) {
+	if kind == nil {
+		panic("kind is nil")
+	}
+	statuses.mu.Lock()
+	defer statuses.mu.Unlock()
+	statuses.m[kind] = status
+}
+
+// StatusOf returns the HTTP status code of the given error by using the nearest Kind in the error tree.
+// The Kind and its ancestors are looked up in the registered status codes. If the error doesn't have a Kind,
+// context.Canceled and context.DeadlineExceeded are mapped to StatusClientClosedRequest and
+// http.StatusGatewayTimeout. Otherwise, it returns http.StatusInternalServerError.
+// It returns http.StatusOK if err is nil.
+func StatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	statuses.mu.RLock()
+	for k := erf.KindOf(err); k != nil; k = k.Parent() {
+		if status, ok := statuses.m[k]; ok {
+			statuses.mu.RUnlock()
+			return status
+		}
+	}
+	statuses.mu.RUnlock()
+	switch {
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// NewProblem creates the problem details of the given error for the request r.
+// The detail is the error message for the client errors, it is only shown for the server errors in debug mode.
+// The JSON encoded Erf is shown in debug mode, and it is shown without the stack traces for the client errors
+// if Chain is enabled.
+func (c *Config) NewProblem(r *http.Request, err error) *Problem {
+	status := c.status(err)
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if p.Title == "" {
+		p.Title = "Unknown Error"
+	}
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	if status < http.StatusInternalServerError || c.Debug {
+		p.Detail = err.Error()
+	}
+	if code := erf.CodeOf(err); code != nil {
+		p.Code = &ProblemCode{
+			Number: code.Number(),
+			Name:   code.Name(),
+		}
+	}
+	if kind := erf.KindOf(err); kind != nil {
+		p.Kind = kind.Name()
+	}
+	for _, tag := range c.PublicTags {
+		if value, ok := erf.LookupTag(err, tag); ok {
+			if p.Tags == nil {
+				p.Tags = make(map[string]interface{}, len(c.PublicTags))
+			}
+			p.Tags[tag] = value
+		}
+	}
+	if c.Debug || (c.Chain && status < http.StatusInternalServerError) {
+		var e *erf.Erf
+		if !errors.As(err, &e) {
+			e = erf.CaptureNone.Wrap(err).(*erf.Erf)
+		}
+		p.Erf, _ = json.Marshal(e)
+		if !c.Debug {
+			p.Erf = stripStackTraces(p.Erf)
+		}
+	}
+	return p
+}
+
+// stripStackTraces removes the stack traces from the JSON encoded Erf.
+func stripStackTraces(data []byte) json.RawMessage {
+	var j struct {
+		Version int                          `json:"version"`
+		Errors  []map[string]json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil
+	}
+	for _, item := range j.Errors {
+		delete(item, "stack")
+		delete(item, "stack_truncated")
+		delete(item, "return_trace")
+		delete(item, "spawned_at")
+	}
+	data, _ = json.Marshal(&j)
+	return data
+}
+
+// WriteError logs the given error and writes its problem details to w as the response of the request r.
+// WriteError doesn't affect if err is nil.
+func (c *Config) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	p := c.NewProblem(r, err)
+	c.log(r, p.Status, err)
+	data, e := json.Marshal(p)
+	if e != nil {
+		p.Tags = nil
+		data, _ = json.Marshal(p)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(data)
+}
+
+// Handler returns an http.Handler that calls h, and recovers the panics of h as Erf objects by using erf.NewPanic.
+// The recovered panics are written by using WriteError. The panics with the value http.ErrAbortHandler are
+// not recovered, to abort the response.
+func (c *Config) Handler(h http.Handler) http.Handler {
+	return c.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		h.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// HandlerFunc returns an http.Handler that calls fn, and writes the error returned by fn by using WriteError.
+// The panics of fn are handled like Handler.
+// If fn has already sent the response header, the error is only logged, and its problem details aren't written.
+func (c *Config) HandlerFunc(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w2, rw := newResponseWriter(w)
+		err := c.call(fn, w2, r)
+		if err == nil {
+			return
+		}
+		if rw.wroteHeader {
+			c.log(r, c.status(err), err)
+			return
+		}
+		c.WriteError(w, r, err)
+	})
+}
+
+func (c *Config) call(fn func(w http.ResponseWriter, r *http.Request) error, w http.ResponseWriter,
+	r *http.Request) (err error) {
+	defer erf.RecoverFunc(func(e *erf.Erf) {
+		if errors.Is(e, http.ErrAbortHandler) {
+			panic(http.ErrAbortHandler)
+		}
+		err = e
+	})
+	return fn(w, r)
+}
+
+func (c *Config) status(err error) int {
+	if c.StatusFunc != nil {
+		return c.StatusFunc(err)
+	}
+	return StatusOf(err)
+}
+
+func (c *Config) log(r *http.Request, status int, err error) {
+	if status < http.StatusInternalServerError && !c.LogClientErrors {
+		return
+	}
+	logf := log.Printf
+	if c.Logger != nil {
+		logf = c.Logger.Printf
+	}
+	if r != nil && r.URL != nil {
+		logf("http error %d on %s %s:\n%+x", status, r.Method, r.URL.Path, err)
+		return
+	}
+	logf("http error %d:\n%+x", status, err)
+}
+
+// WriteError is similar with the method WriteError of DefaultConfig.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultConfig.WriteError(w, r, err)
+}
+
+// Handler is similar with the method Handler of DefaultConfig.
+func Handler(h http.Handler) http.Handler {
+	return DefaultConfig.Handler(h)
+}
+
+// HandlerFunc is similar with the method HandlerFunc of DefaultConfig.
+func HandlerFunc(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return DefaultConfig.HandlerFunc(fn)
+}
@@ -0,0 +1,110 @@
+package httperf_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/goinsane/erf"
+	"github.com/goinsane/erf/httperf"
+)
+
+func ExampleConfig_HandlerFunc() {
+	cfg := &httperf.Config{
+		Logger:     log.New(io.Discard, "", 0),
+		PublicTags: []string{"name"},
+	}
+	h := cfg.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/panic" {
+			panic("an example panic")
+		}
+		return erf.Newf("invalid argument %q", "x").Attach("name").WithKind(erf.KindInvalidArgument)
+	})
+
+	for _, path := range []string{"/foo", "/panic"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		fmt.Println(w.Code, w.Header().Get("Content-Type"))
+		fmt.Println(w.Body.String())
+	}
+
+	// Output:
+	// 400 application/problem+json
+	// {"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid argument \"x\"","instance":"/foo","kind":"invalid_argument","tags":{"name":"x"}}
+	// 500 application/problem+json
+	// {"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/panic"}
+}
+
+func ExampleStatusOf() {
+	kindUserNotFound := erf.NewKind("example_http_user_not_found", erf.KindNotFound)
+
+	fmt.Println(httperf.StatusOf(erf.New("an example erf error").WithKind(kindUserNotFound)))
+	fmt.Println(httperf.StatusOf(erf.New("an example erf error")))
+
+	// Output:
+	// 404
+	// 500
+}
+
+func ExampleConfig_HandlerFunc_headerSent() {
+	buf := bytes.NewBuffer(nil)
+	cfg := &httperf.Config{
+		Logger: log.New(buf, "", 0),
+	}
+	h := cfg.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, "partial response")
+		return erf.New("an example erf error after the header")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	fmt.Printf("%d %q\n", w.Code, w.Header().Get("Content-Type"))
+	fmt.Println(w.Body.String())
+
+	fmt.Println("the error is only logged.")
+	fmt.Println(strings.SplitN(buf.String(), "\n", 2)[0])
+
+	// Output:
+	// 200 ""
+	// partial response
+	// the error is only logged.
+	// http error 500 on GET /stream:
+}
+
+func ExampleHandler() {
+	h := httperf.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flusher := w.(http.Flusher)
+		_, hijacker := w.(http.Hijacker)
+		_, readerFrom := w.(io.ReaderFrom)
+		_, pusher := w.(http.Pusher)
+		_, _ = fmt.Fprintln(w, flusher, hijacker, readerFrom, pusher)
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	fmt.Println("the optional interfaces of the HTTP/1.1 response writer are kept.")
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	fmt.Print(string(body))
+
+	fmt.Println("the optional interfaces of the recorder are kept.")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	fmt.Print(w.Body.String())
+
+	// Output:
+	// the optional interfaces of the HTTP/1.1 response writer are kept.
+	// true true true false
+	// the optional interfaces of the recorder are kept.
+	// true false false false
+}
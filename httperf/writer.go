@@ -0,0 +1,195 @@
+package httperf
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// responseWriter is an http.ResponseWriter that tracks whether the response header was sent.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+const (
+	rwFlusher = 1 << iota
+	rwHijacker
+	rwReaderFrom
+	rwPusher
+)
+
+// newResponseWriter wraps w into a responseWriter, and returns the result that implements the same optional
+// interfaces http.Flusher, http.Hijacker, io.ReaderFrom and http.Pusher with w.
+func newResponseWriter(w http.ResponseWriter) (http.ResponseWriter, *responseWriter) {
+	rw := &responseWriter{
+		ResponseWriter: w,
+	}
+	var ifcs int
+	if _, ok := w.(http.Flusher); ok {
+		ifcs |= rwFlusher
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		ifcs |= rwHijacker
+	}
+	if _, ok := w.(io.ReaderFrom); ok {
+		ifcs |= rwReaderFrom
+	}
+	if _, ok := w.(http.Pusher); ok {
+		ifcs |= rwPusher
+	}
+	switch ifcs {
+	case 0:
+		return rw, rw
+	case rwFlusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+		}{rw, responseFlusher{rw}}, rw
+	case rwHijacker:
+		return struct {
+			*responseWriter
+			http.Hijacker
+		}{rw, responseHijacker{rw}}, rw
+	case rwFlusher | rwHijacker:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+		}{rw, responseFlusher{rw}, responseHijacker{rw}}, rw
+	case rwReaderFrom:
+		return struct {
+			*responseWriter
+			io.ReaderFrom
+		}{rw, responseReaderFrom{rw}}, rw
+	case rwFlusher | rwReaderFrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{rw, responseFlusher{rw}, responseReaderFrom{rw}}, rw
+	case rwHijacker | rwReaderFrom:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, responseHijacker{rw}, responseReaderFrom{rw}}, rw
+	case rwFlusher | rwHijacker | rwReaderFrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, responseFlusher{rw}, responseHijacker{rw}, responseReaderFrom{rw}}, rw
+	case rwPusher:
+		return struct {
+			*responseWriter
+			http.Pusher
+		}{rw, responsePusher{rw}}, rw
+	case rwFlusher | rwPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Pusher
+		}{rw, responseFlusher{rw}, responsePusher{rw}}, rw
+	case rwHijacker | rwPusher:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			http.Pusher
+		}{rw, responseHijacker{rw}, responsePusher{rw}}, rw
+	case rwFlusher | rwHijacker | rwPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{rw, responseFlusher{rw}, responseHijacker{rw}, responsePusher{rw}}, rw
+	case rwReaderFrom | rwPusher:
+		return struct {
+			*responseWriter
+			io.ReaderFrom
+			http.Pusher
+		}{rw, responseReaderFrom{rw}, responsePusher{rw}}, rw
+	case rwFlusher | rwReaderFrom | rwPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{rw, responseFlusher{rw}, responseReaderFrom{rw}, responsePusher{rw}}, rw
+	case rwHijacker | rwReaderFrom | rwPusher:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{rw, responseHijacker{rw}, responseReaderFrom{rw}, responsePusher{rw}}, rw
+	case rwFlusher | rwHijacker | rwReaderFrom | rwPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{rw, responseFlusher{rw}, responseHijacker{rw}, responseReaderFrom{rw}, responsePusher{rw}}, rw
+	}
+	return rw, rw
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if statusCode >= http.StatusOK {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type responseFlusher struct {
+	*responseWriter
+}
+
+// Flush is implementation of http.Flusher.
+func (w responseFlusher) Flush() {
+	w.wroteHeader = true
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+type responseHijacker struct {
+	*responseWriter
+}
+
+// Hijack is implementation of http.Hijacker.
+func (w responseHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+type responseReaderFrom struct {
+	*responseWriter
+}
+
+// ReadFrom is implementation of io.ReaderFrom.
+func (w responseReaderFrom) ReadFrom(r io.Reader) (int64, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+}
+
+type responsePusher struct {
+	*responseWriter
+}
+
+// Push is implementation of http.Pusher.
+func (w responsePusher) Push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
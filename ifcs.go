@@ -5,3 +5,19 @@ type WrappedError interface {
 	error
 	Unwrap() error
 }
+
+// MultiWrappedError is an interface to simulate GoLang's wrapped errors that wrap multiple errors.
+type MultiWrappedError interface {
+	error
+	Unwrap() []error
+}
+
+// TracedError is an interface for the errors that have StackTrace and tags.
+// Erf and the types that embed Erf implement TracedError, and they are shown with their StackTrace's and tags
+// by Erf.Format and encoded with them by Erf.MarshalJSON.
+type TracedError interface {
+	error
+	StackTrace() *StackTrace
+	Tags() []string
+	Tag(tag string) interface{}
+}
@@ -0,0 +1,441 @@
+package erf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// JSONVersion is the version of the JSON schema used by Erf.MarshalJSON and Erf.UnmarshalJSON.
+//
+// The schema of version 2 is:
+// 	{
+// 	  "version": 2,
+// 	  "errors": [
+// 	    {
+// 	      "message": "invalid argument \"x\": value below zero",
+// 	      "erf": true,
+// 	      "fmt": "invalid argument %q: %w",
+// 	      "args": ["x", "value below zero"],
+// 	      "tags": [{"name": "name", "index": 0}],
+// 	      "attrs": [{"key": "user_id", "value": 42}],
+// 	      "code": {"number": 1001, "name": "user_not_found"},
+// 	      "kind": "not_found",
+// 	      "stack": [{"function": "main.Foo", "file": "/src/main.go", "line": 21, "pc_offset": 83}],
+// 	      "wrapped": [1]
+// 	    },
+// 	    {
+// 	      "message": "value below zero"
+// 	    }
+// 	  ]
+// 	}
+//
+// The field "errors" is the list of errors that returned by Erf.UnwrapAll, the first element is the Erf itself.
+// The field "wrapped" is the list of indexes of the errors that directly wrapped by the error, it is omitted if
+// the error doesn't wrap any error.
+// The fields "fmt", "args", "tags", "attrs", "code", "kind" and "stack" are only given for TracedError elements that
+// are marked with "erf". The field "tags" has only the tags attached to arguments, and the field "attrs" has
+// the attributes. The fields "code" and "kind" are omitted if the error doesn't have a Code or a Kind, they are
+// decoded as the registered Code and Kind if they are registered, otherwise as new unregistered ones.
+// The field "stack" is omitted if the stack wasn't captured, and the field "stack_truncated" is true if
+// the stack was truncated. The field "return_trace" is the return trace in the same format with "stack", and
+// it is omitted if the error wasn't traced. The field "spawned_at" is the list of the stack traces of the spawn
+// points in the same format with "stack", and it is omitted if the error doesn't have any spawn point.
+// The arguments and the values of the attributes are encoded with encoding/json, error arguments are encoded as
+// their messages and arguments that can't be encoded are encoded as strings by using fmt.Sprintf("%v", arg).
+//
+// The schema of version 1 is same with version 2 except that it doesn't have the field "wrapped", and
+// each error wraps the next error in the list. UnmarshalJSON can decode both of versions.
+const JSONVersion = 2
+
+type jsonErf struct {
+	Version int         `json:"version"`
+	Errors  []jsonError `json:"errors"`
+}
+
+type jsonError struct {
+	Message        string            `json:"message"`
+	Erf            bool              `json:"erf,omitempty"`
+	Fmt            string            `json:"fmt,omitempty"`
+	Args           []json.RawMessage `json:"args,omitempty"`
+	Tags           []jsonTag         `json:"tags,omitempty"`
+	Attrs          []jsonAttr        `json:"attrs,omitempty"`
+	Code           *jsonCode         `json:"code,omitempty"`
+	Kind           string            `json:"kind,omitempty"`
+	Stack          *StackTrace       `json:"stack,omitempty"`
+	StackTruncated bool              `json:"stack_truncated,omitempty"`
+	ReturnTrace    *StackTrace       `json:"return_trace,omitempty"`
+	SpawnedAt      []*StackTrace     `json:"spawned_at,omitempty"`
+	Wrapped        []int             `json:"wrapped,omitempty"`
+}
+
+type jsonTag struct {
+	Name  string `json:"name"`
+	Index int    `json:"index"`
+}
+
+type jsonAttr struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonCode struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+}
+
+type jsonStackCaller struct {
+	Function string  `json:"function"`
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	PCOffset uintptr `json:"pc_offset"`
+}
+
+// decodedError is the underlying error of the errors that decoded by Erf.UnmarshalJSON.
+type decodedError struct {
+	text string
+	err  error
+}
+
+func (e *decodedError) Error() string {
+	return e.text
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.err
+}
+
+// decodedErrors is the underlying error of the errors that decoded by Erf.UnmarshalJSON, if the error wraps
+// multiple errors.
+type decodedErrors struct {
+	text string
+	errs []error
+}
+
+func (e *decodedErrors) Error() string {
+	return e.text
+}
+
+func (e *decodedErrors) Unwrap() []error {
+	return e.errs
+}
+
+// MarshalJSON is implementation of json.Marshaler.
+// MarshalJSON encodes Erf and all of wrapped errors by using the schema described in JSONVersion.
+func (e *Erf) MarshalJSON() ([]byte, error) {
+	j := &jsonErf{
+		Version: JSONVersion,
+		Errors:  make([]jsonError, 0, 16),
+	}
+	var add func(err error) int
+	add = func(err error) int {
+		index := len(j.Errors)
+		item := jsonError{
+			Message: err.Error(),
+		}
+		if e2, ok := err.(TracedError); ok {
+			item.Erf = true
+			if e3, ok := e2.(interface {
+				Fmt() string
+				Args() []interface{}
+			}); ok {
+				item.Fmt = e3.Fmt()
+				if args := e3.Args(); args != nil {
+					item.Args = make([]json.RawMessage, 0, len(args))
+					for _, arg := range args {
+						item.Args = append(item.Args, marshalJSONArg(arg))
+					}
+				}
+			}
+			if e3, ok := e2.(interface{ TagIndex(tag string) int }); ok {
+				for _, tag := range e2.Tags() {
+					index := e3.TagIndex(tag)
+					if index < 0 {
+						continue
+					}
+					item.Tags = append(item.Tags, jsonTag{
+						Name:  tag,
+						Index: index,
+					})
+				}
+			}
+			if e3, ok := e2.(interface{ Attrs() []KeyValue }); ok {
+				for _, attr := range e3.Attrs() {
+					item.Attrs = append(item.Attrs, jsonAttr{
+						Key:   attr.Key,
+						Value: marshalJSONArg(attr.Value),
+					})
+				}
+			}
+			if e3, ok := e2.(interface {
+				Code() *Code
+				Kind() *Kind
+			}); ok {
+				if c := e3.Code(); c != nil {
+					item.Code = &jsonCode{
+						Number: c.Number(),
+						Name:   c.Name(),
+					}
+				}
+				if k := e3.Kind(); k != nil {
+					item.Kind = k.Name()
+				}
+			}
+			item.Stack = e2.StackTrace()
+			item.StackTruncated = item.Stack.Truncated()
+			if e3, ok := e2.(interface{ ReturnTrace() *StackTrace }); ok {
+				item.ReturnTrace = e3.ReturnTrace()
+			}
+			if e3, ok := e2.(interface{ SpawnTraces() []*StackTrace }); ok {
+				item.SpawnedAt = e3.SpawnTraces()
+			}
+		}
+		j.Errors = append(j.Errors, item)
+		for _, err2 := range unwrapErrors(err) {
+			wrapped := add(err2)
+			j.Errors[index].Wrapped = append(j.Errors[index].Wrapped, wrapped)
+		}
+		return index
+	}
+	add(e)
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON is implementation of json.Unmarshaler.
+// UnmarshalJSON decodes Erf and all of wrapped errors by using the schema described in JSONVersion.
+// The decoded Erf doesn't have program counters, its StackTrace is built from the decoded callers.
+func (e *Erf) UnmarshalJSON(data []byte) error {
+	var j jsonErf
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != 1 && j.Version != JSONVersion {
+		return fmt.Errorf("unsupported json version %d", j.Version)
+	}
+	if len(j.Errors) <= 0 || !j.Errors[0].Erf {
+		return errors.New("first error is not erf")
+	}
+	decoded := make([]error, len(j.Errors))
+	for i := len(j.Errors) - 1; i >= 0; i-- {
+		item := j.Errors[i]
+		wrapped := item.Wrapped
+		if j.Version == 1 {
+			wrapped = nil
+			if i+1 < len(j.Errors) {
+				wrapped = []int{i + 1}
+			}
+		}
+		var de error
+		switch len(wrapped) {
+		case 0:
+			de = &decodedError{
+				text: item.Message,
+			}
+		case 1:
+			if wrapped[0] <= i || wrapped[0] >= len(j.Errors) {
+				return errors.New("wrapped index out of range")
+			}
+			de = &decodedError{
+				text: item.Message,
+				err:  decoded[wrapped[0]],
+			}
+		default:
+			errs := make([]error, 0, len(wrapped))
+			for _, index := range wrapped {
+				if index <= i || index >= len(j.Errors) {
+					return errors.New("wrapped index out of range")
+				}
+				errs = append(errs, decoded[index])
+			}
+			de = &decodedErrors{
+				text: item.Message,
+				errs: errs,
+			}
+		}
+		if !item.Erf {
+			decoded[i] = de
+			continue
+		}
+		e2 := &Erf{
+			err:         de,
+			format:      item.Fmt,
+			syntheticST: item.Stack,
+			returnST:    item.ReturnTrace,
+		}
+		for k := len(item.SpawnedAt) - 1; k >= 0; k-- {
+			if item.SpawnedAt[k] == nil {
+				return errors.New("spawn stack trace is null")
+			}
+			e2.spawn = &spawn{
+				st:     item.SpawnedAt[k],
+				parent: e2.spawn,
+			}
+		}
+		if e2.syntheticST != nil {
+			e2.captured = true
+			e2.truncated = item.StackTruncated
+			e2.syntheticST.truncated = item.StackTruncated
+		}
+		if item.Args != nil {
+			e2.args = make([]interface{}, 0, len(item.Args))
+			for _, raw := range item.Args {
+				var arg interface{}
+				if err := json.Unmarshal(raw, &arg); err != nil {
+					return err
+				}
+				e2.args = append(e2.args, arg)
+			}
+		}
+		if item.Tags != nil {
+			e2.tags = make([]string, 0, len(item.Tags))
+			e2.tagIndexes = make(map[string]int, len(item.Tags))
+			for _, tag := range item.Tags {
+				if tag.Name == "" {
+					return errors.New("tag name is empty")
+				}
+				if tag.Index < 0 || tag.Index >= len(e2.args) {
+					return errors.New("tag index out of range")
+				}
+				if _, ok := e2.tagIndexes[tag.Name]; ok {
+					return errors.New("tag already defined")
+				}
+				e2.tags = append(e2.tags, tag.Name)
+				e2.tagIndexes[tag.Name] = tag.Index
+			}
+		}
+		if item.Attrs != nil {
+			e2.attrs = make([]KeyValue, 0, len(item.Attrs))
+			for _, attr := range item.Attrs {
+				if attr.Key == "" {
+					return errors.New("attribute key is empty")
+				}
+				if _, ok := e2.tagIndexes[attr.Key]; ok {
+					return errors.New("tag already defined")
+				}
+				var value interface{}
+				if err := json.Unmarshal(attr.Value, &value); err != nil {
+					return err
+				}
+				if value == nil {
+					continue
+				}
+				e2.attrs = setAttr(e2.attrs, attr.Key, value)
+			}
+		}
+		if item.Kind != "" {
+			e2.kind = decodeKind(item.Kind)
+		}
+		if item.Code != nil {
+			e2.code = decodeCode(item.Code.Number, item.Code.Name, e2.kind)
+			if e2.kind == e2.code.kind {
+				e2.kind = nil
+			}
+		}
+		decoded[i] = e2
+	}
+	e.assign(decoded[0].(*Erf))
+	return nil
+}
+
+// MarshalJSON is implementation of json.Marshaler.
+func (t *StackTrace) MarshalJSON() ([]byte, error) {
+	callers := t.callers
+	if callers == nil {
+		callers = []StackCaller{}
+	}
+	return json.Marshal(callers)
+}
+
+// UnmarshalJSON is implementation of json.Unmarshaler.
+// The decoded StackTrace doesn't have program counters.
+func (t *StackTrace) UnmarshalJSON(data []byte) error {
+	var callers []StackCaller
+	if err := json.Unmarshal(data, &callers); err != nil {
+		return err
+	}
+	*t = *NewStackTraceFromCallers(callers...)
+	return nil
+}
+
+// MarshalJSON is implementation of json.Marshaler.
+func (c StackCaller) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonStackCaller{
+		Function: c.Function,
+		File:     c.File,
+		Line:     c.Line,
+		PCOffset: c.PC - c.Entry,
+	})
+}
+
+// UnmarshalJSON is implementation of json.Unmarshaler.
+// The decoded StackCaller has zero Entry, and PC is the decoded pc offset.
+func (c *StackCaller) UnmarshalJSON(data []byte) error {
+	var j jsonStackCaller
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*c = StackCaller{
+		Frame: runtime.Frame{
+			PC:       j.PCOffset,
+			Function: j.Function,
+			File:     j.File,
+			Line:     j.Line,
+		},
+	}
+	return nil
+}
+
+// assign assigns the fields of the decoded Erf top to e.
+func (e *Erf) assign(top *Erf) {
+	e.err = top.err
+	e.format = top.format
+	e.args = top.args
+	e.tags = top.tags
+	e.tagIndexes = top.tagIndexes
+	e.attrs = top.attrs
+	e.diags = top.diags
+	e.code = top.code
+	e.kind = top.kind
+	e.pc = top.pc
+	e.captured = top.captured
+	e.truncated = top.truncated
+	e.syntheticST = top.syntheticST
+	e.returnPC = top.returnPC
+	e.returnST = top.returnST
+	e.spawn = top.spawn
+	e.origin = top.origin
+}
+
+// decodeKind returns the registered Kind with the given name, or a new unregistered Kind.
+func decodeKind(name string) *Kind {
+	if k := LookupKind(name); k != nil {
+		return k
+	}
+	return &Kind{
+		name: name,
+	}
+}
+
+// decodeCode returns the registered Code with the given number and name, or a new unregistered Code.
+func decodeCode(number int, name string, kind *Kind) *Code {
+	if c := LookupCode(number); c != nil && c.name == name {
+		return c
+	}
+	return &Code{
+		number: number,
+		name:   name,
+		kind:   kind,
+	}
+}
+
+func marshalJSONArg(arg interface{}) json.RawMessage {
+	if err, ok := arg.(error); ok {
+		arg = err.Error()
+	}
+	b, err := json.Marshal(arg)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", arg))
+	}
+	return b
+}
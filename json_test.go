@@ -0,0 +1,58 @@
+package erf_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleErf_MarshalJSON() {
+	e := erf.CaptureNone.New("an example erf error")
+	err := erf.CaptureNone.Newf("we have an example error on %q: %w", "foo", e).Attach("name")
+
+	data, _ := json.Marshal(err)
+	fmt.Printf("%s\n\n", data)
+
+	var e2 erf.Erf
+	_ = json.Unmarshal(data, &e2)
+
+	fmt.Println("decoded error message.")
+	fmt.Printf("%v\n\n", &e2)
+
+	fmt.Println("decoded tags.")
+	fmt.Printf("%v %v\n\n", e2.Tags(), e2.Tag("name"))
+
+	fmt.Println("list all decoded error messages by using indent and show decoded tags of errors.")
+	printLines(fmt.Sprintf("%+x", &e2))
+
+	err2 := erf.CaptureCaller.Wrap(err)
+	data, _ = json.Marshal(err2)
+	var e3 erf.Erf
+	_ = json.Unmarshal(data, &e3)
+
+	fmt.Println("decoded StackTrace.")
+	fmt.Println(e3.StackTrace().Len(), e3.StackTrace().Synthetic(), e3.StackTrace().Caller(0).Function)
+	fmt.Println(e3.StackTrace().Caller(0).Line == err2.(*erf.Erf).StackTrace().Caller(0).Line)
+
+	// Output:
+	// {"version":2,"errors":[{"message":"we have an example error on \"foo\": an example erf error","erf":true,"fmt":"we have an example error on %q: %w","args":["foo","an example erf error"],"tags":[{"name":"name","index":0}],"wrapped":[1]},{"message":"an example erf error","erf":true}]}
+	//
+	// decoded error message.
+	// we have an example error on "foo": an example erf error
+	//
+	// decoded tags.
+	// [name] foo
+	//
+	// list all decoded error messages by using indent and show decoded tags of errors.
+	// 	we have an example error on "foo": an example erf error
+	// * stack trace not captured
+	// + "name"="foo"
+	//
+	// 	an example erf error
+	// * stack trace not captured
+	//
+	// decoded StackTrace.
+	// 1 true github.com/goinsane/erf_test.ExampleErf_MarshalJSON
+	// true
+}
@@ -0,0 +1,186 @@
+package erf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+var (
+	// ErrArgIsNil is the diagnostic of the nil arguments.
+	ErrArgIsNil = errors.New("arg is nil")
+
+	// ErrTagsAlreadyAttached is the diagnostic of calling Attach more than once.
+	ErrTagsAlreadyAttached = errors.New("tags are already attached")
+
+	// ErrTooManyTags is the diagnostic of attaching more tags than arguments.
+	ErrTooManyTags = errors.New("number of tags is more than args")
+
+	// ErrTagAlreadyDefined is the diagnostic of the duplicate tags and attribute keys.
+	ErrTagAlreadyDefined = errors.New("tag already defined")
+
+	// ErrMissingValue is the diagnostic of the key/value pairs that have a key without value.
+	ErrMissingValue = errors.New("missing value of key")
+
+	// ErrKeyNotString is the diagnostic of the attribute keys that are not string.
+	ErrKeyNotString = errors.New("key must be string")
+
+	// ErrKeyEmpty is the diagnostic of the empty attribute keys.
+	ErrKeyEmpty = errors.New("key is empty")
+
+	// ErrValueIsNil is the diagnostic of the nil attribute values.
+	ErrValueIsNil = errors.New("value is nil")
+)
+
+// LenientEnv is the name of the environment variable that enables the lenient mode when the program starts.
+// The value of the environment variable is parsed by using strconv.ParseBool.
+const LenientEnv = "ERF_LENIENT"
+
+var lenient int32
+
+func init() {
+	if s, ok := os.LookupEnv(LenientEnv); ok {
+		if b, err := strconv.ParseBool(s); err == nil {
+			SetLenient(b)
+		}
+	}
+}
+
+// Lenient reports whether the lenient mode is enabled.
+// The lenient mode is never enabled in the strict builds that built with the build tag "erfstrict".
+func Lenient() bool {
+	return !strictBuild && atomic.LoadInt32(&lenient) != 0
+}
+
+// SetLenient enables or disables the lenient mode. The lenient mode is disabled by default.
+// In the lenient mode, the functions and methods that panic on invalid arguments don't panic. Instead of panicking,
+// they ignore the invalid arguments and record the problems as diagnostics on Erf:
+// 	the nil arguments of Newf and Errorf are rendered as "<nil>",
+// 	the invalid tags of Attach are skipped,
+// 	the invalid key/value pairs of With are skipped.
+// SetLenient has no effect in the strict builds that built with the build tag "erfstrict".
+func SetLenient(b bool) {
+	var v int32
+	if b {
+		v = 1
+	}
+	atomic.StoreInt32(&lenient, v)
+}
+
+// Diagnostics returns the problems that recorded in the lenient mode. It returns nil if there is no problem.
+// The diagnostics are one of the errors ErrArgIsNil, ErrTagsAlreadyAttached, ErrTooManyTags, ErrTagAlreadyDefined,
+// ErrMissingValue, ErrKeyNotString, ErrKeyEmpty and ErrValueIsNil.
+func (e *Erf) Diagnostics() []error {
+	if e.diags == nil {
+		return nil
+	}
+	result := make([]error, len(e.diags))
+	copy(result, e.diags)
+	return result
+}
+
+// TryAttach is similar with Attach except that it returns the problem as an error instead of panicking,
+// regardless of the lenient mode. It doesn't attach any tag if there is a problem.
+func (e *Erf) TryAttach(tags ...string) error {
+	if diags := e.attach(tags, false); len(diags) > 0 {
+		return diags[0]
+	}
+	return nil
+}
+
+// TryWith is similar with With except that it returns the problem as an error instead of panicking,
+// regardless of the lenient mode. It doesn't add any attribute if there is a problem.
+func (e *Erf) TryWith(kv ...interface{}) error {
+	if diags := e.with(kv, false); len(diags) > 0 {
+		return diags[0]
+	}
+	return nil
+}
+
+func (e *Erf) diagnose(diags []error, lenient bool) {
+	if len(diags) <= 0 {
+		return
+	}
+	if !lenient {
+		panic(diags[0].Error())
+	}
+	e.diags = append(e.diags[:len(e.diags):len(e.diags)], diags...)
+}
+
+// nilArg is the substitute of the nil arguments in the lenient mode.
+// nilArg doesn't implement error, so the verbs '%w' of the nil arguments are rewritten by unwrapNilArgs.
+type nilArg struct{}
+
+func (nilArg) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte("<nil>"))
+}
+
+// unwrapNilArgs rewrites the verbs '%w' of the arguments that are nilArg in format as '%v', so the nil arguments
+// are rendered as "<nil>" and nothing is wrapped instead of them.
+func unwrapNilArgs(format string, args []interface{}) string {
+	var result []byte
+	argNum := 0
+	index := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		j := i + 1
+		for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+			j++
+		}
+		if j >= len(format) || format[j] != ']' {
+			return i
+		}
+		if n, err := strconv.Atoi(format[i+1 : j]); err == nil {
+			argNum = n - 1
+		}
+		return j + 1
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && (format[i] == '#' || format[i] == '0' || format[i] == '+' || format[i] == '-' ||
+			format[i] == ' ') {
+			i++
+		}
+		i = index(i)
+		if i < len(format) && format[i] == '*' {
+			argNum++
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i = index(i + 1)
+			if i < len(format) && format[i] == '*' {
+				argNum++
+				i++
+			}
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+		i = index(i)
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		if format[i] == 'w' && argNum >= 0 && argNum < len(args) {
+			if _, ok := args[argNum].(nilArg); ok {
+				if result == nil {
+					result = []byte(format)
+				}
+				result[i] = 'v'
+			}
+		}
+		argNum++
+	}
+	if result == nil {
+		return format
+	}
+	return string(result)
+}
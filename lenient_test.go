@@ -0,0 +1,64 @@
+//go:build !erfstrict
+// +build !erfstrict
+
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleSetLenient() {
+	erf.SetLenient(true)
+	defer erf.SetLenient(false)
+
+	err := erf.Newf("user %v: %s not found", nil, "alice").Attach("user", "name", "extra").With("id")
+
+	fmt.Println(err)
+	fmt.Println(err.Tags())
+	fmt.Println(err.Diagnostics())
+
+	err2 := erf.Errorf("request %[2]q failed: %[1]w, %[3]w", nil, "foo", errors.New("an example error"))
+	fmt.Println(err2)
+	fmt.Println(err2.(*erf.Erf).Diagnostics(), len(err2.(*erf.Erf).UnwrapMulti()), errors.Unwrap(err2))
+
+	// Output:
+	// user <nil>: alice not found
+	// [user name]
+	// [arg is nil number of tags is more than args missing value of key]
+	// request "foo" failed: <nil>, an example error
+	// [arg is nil] 1 an example error
+}
+
+func ExampleErf_TryAttach() {
+	err := erf.Newf("invalid argument %q: value %d below zero", "x", -1)
+
+	fmt.Println(err.TryAttach("name", "value", "extra"))
+	fmt.Println(errors.Is(err.TryAttach("name", "name"), erf.ErrTagAlreadyDefined))
+	fmt.Println(err.TryAttach("name", "value"), err.Tags())
+
+	// Output:
+	// number of tags is more than args
+	// true
+	// <nil> [name value]
+}
+
+func ExampleErf_Diagnostics() {
+	erf.SetLenient(true)
+	defer erf.SetLenient(false)
+
+	err := erf.CaptureNone.Newf("user %v not found", nil).With("id", nil, "", 1)
+
+	fmt.Println(err.Diagnostics())
+	printLines(fmt.Sprintf("%+x", err))
+
+	// Output:
+	// [arg is nil value is nil key is empty]
+	// 	user <nil> not found
+	// * stack trace not captured
+	// ! arg is nil
+	// ! value is nil
+	// ! key is empty
+}
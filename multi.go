@@ -0,0 +1,146 @@
+package erf
+
+import (
+	"fmt"
+	"io"
+)
+
+// MultiError is an error type that combines several errors, and keeps each member's StackTrace and tags.
+type MultiError struct {
+	errs []error
+}
+
+// Join returns a MultiError that combines the given errors. Nil errors are discarded.
+// It returns nil if every error is nil.
+func Join(errs ...error) error {
+	m := &MultiError{
+		errs: make([]error, 0, len(errs)),
+	}
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+	if len(m.errs) <= 0 {
+		return nil
+	}
+	return m
+}
+
+// Append appends the given errors to err, and returns a new MultiError. Nil errors are discarded.
+// If err or any of errs is a MultiError, its members are appended instead of itself.
+// Append doesn't modify err. It returns nil if every error is nil.
+func Append(err error, errs ...error) error {
+	m := &MultiError{
+		errs: make([]error, 0, 1+len(errs)),
+	}
+	for _, err := range append([]error{err}, errs...) {
+		if err == nil {
+			continue
+		}
+		if m2, ok := err.(*MultiError); ok {
+			m.errs = append(m.errs, m2.errs...)
+			continue
+		}
+		m.errs = append(m.errs, err)
+	}
+	if len(m.errs) <= 0 {
+		return nil
+	}
+	return m
+}
+
+// Error is implementation of error.
+// It returns the numbered list of the error messages of all members.
+func (m *MultiError) Error() string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeInt(buf, len(m.errs))
+	if len(m.errs) == 1 {
+		buf.WriteString(" error occurred:")
+	} else {
+		buf.WriteString(" errors occurred:")
+	}
+	for idx, err := range m.errs {
+		buf.WriteString("\n\t")
+		writeInt(buf, idx+1)
+		buf.WriteString(". ")
+		msg := err.Error()
+		for i := 0; i < len(msg); i++ {
+			buf.WriteByte(msg[i])
+			if msg[i] == '\n' {
+				buf.WriteString("\t   ")
+			}
+		}
+	}
+	return buf.String()
+}
+
+// Unwrap returns all members.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors()
+}
+
+// Errors returns all members.
+func (m *MultiError) Errors() []error {
+	result := make([]error, len(m.errs))
+	copy(result, m.errs)
+	return result
+}
+
+// Len returns the number of all members.
+func (m *MultiError) Len() int {
+	return len(m.errs)
+}
+
+// Format is implementation of fmt.Formatter.
+// Format shows the numbered list of the error messages of all members, and shows the full chain of each member
+// by using one more padding char than MultiError, with the same rules of Erf.Format.
+//
+// For '%v' (also '%s'):
+// 	%v       just show the numbered list of the error messages of all members.
+//
+// For '%x' and '%X':
+// 	%x       show the numbered list by using indent and show the full chain of each member.
+// 	%X       show the numbered list by using indent and show only the first error of each member.
+//
+// The flags, the padding and the indent are used like Erf.Format.
+func (m *MultiError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v':
+		_, _ = io.WriteString(f, m.Error())
+		return
+	case 'x', 'X':
+	default:
+		return
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	pad, wid, prec := getPadWidPrec(f)
+	if !f.Flag('-') {
+		writeLines(buf, m.Error(), pad, wid+prec)
+	}
+	for idx, err := range m.errs {
+		if idx > 0 {
+			buf.WriteRune('\n')
+		}
+		writePadding(buf, pad, wid)
+		buf.WriteString("# ")
+		writeInt(buf, idx+1)
+		buf.WriteRune('\n')
+		first := true
+		walkErrors(err, 0, 0, func(err error, depth, branch int) bool {
+			if !first && verb == 'X' {
+				return false
+			}
+			if !first {
+				buf.WriteRune('\n')
+			}
+			first = false
+			formatError(buf, f, err, pad, wid+1+branch, prec)
+			writePadding(buf, pad, wid+1+branch)
+			return true
+		})
+	}
+	_, _ = f.Write(buf.Bytes())
+}
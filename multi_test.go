@@ -0,0 +1,90 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func ExampleJoin() {
+	errFoo := erf.New("an example erf error")
+	errBar := errors.New("an example error")
+
+	err := erf.Join(errFoo, nil, errBar)
+
+	fmt.Println("the returned error matches the members.")
+	fmt.Println(errors.Is(err, errFoo), errors.Is(err, errBar))
+
+	fmt.Println("list all error messages.")
+	fmt.Printf("%v\n", err)
+
+	fmt.Println("the nil errors are discarded.")
+	fmt.Println(erf.Join(nil, nil) == nil, erf.Append(nil, nil) == nil)
+
+	// Output:
+	// the returned error matches the members.
+	// true true
+	// list all error messages.
+	// 2 errors occurred:
+	// 	1. an example erf error
+	// 	2. an example error
+	// the nil errors are discarded.
+	// true true
+}
+
+func ExampleAppend() {
+	var err error
+	for i := 1; i <= 3; i++ {
+		err = erf.Append(err, erf.Newf("an example erf error #%d", i))
+	}
+	err = erf.Append(err, nil)
+
+	fmt.Println(err.(*erf.MultiError).Len())
+	fmt.Printf("%v\n", err)
+
+	// Output:
+	// 3
+	// 3 errors occurred:
+	// 	1. an example erf error #1
+	// 	2. an example erf error #2
+	// 	3. an example erf error #3
+}
+
+func ExampleMultiError_Format() {
+	errFoo := erf.CaptureNone.Errorf("an example erf error: %w", erf.CaptureNone.New("an example cause"))
+	errBar := errors.New("an example error")
+	err := erf.Join(errFoo, errBar)
+
+	fmt.Println("list all error messages by using indent and show the full chain of each member.")
+	printLines(fmt.Sprintf("%x", err))
+
+	fmt.Println("show only the first error of each member.")
+	printLines(fmt.Sprintf("%X", err))
+
+	// Output:
+	// list all error messages by using indent and show the full chain of each member.
+	// 	2 errors occurred:
+	// 		1. an example erf error: an example cause
+	// 		2. an example error
+	// # 1
+	// 		an example erf error: an example cause
+	// 	* stack trace not captured
+	//
+	// 		an example cause
+	// 	* stack trace not captured
+	//
+	// # 2
+	// 		an example error
+	//
+	// show only the first error of each member.
+	// 	2 errors occurred:
+	// 		1. an example erf error: an example cause
+	// 		2. an example error
+	// # 1
+	// 		an example erf error: an example cause
+	// 	* stack trace not captured
+	//
+	// # 2
+	// 		an example error
+}
@@ -0,0 +1,6 @@
+//go:build !erfstrict
+// +build !erfstrict
+
+package erf
+
+const strictBuild = false
@@ -0,0 +1,92 @@
+package erf
+
+import (
+	"strings"
+)
+
+// PanicTag is the tag of the panic value in the Erf objects that created from panics.
+const PanicTag = "panic"
+
+// Recover recovers a panic and puts an Erf object created from the panic value onto the given pointer.
+// Recover must be deferred directly like the following:
+// 	func Foo() (err error) {
+// 		defer erf.Recover(&err)
+// 		...
+// 	}
+// Recover doesn't affect if there is no panic. If perr is nil, the panic is recovered and discarded.
+// See also NewPanic.
+func Recover(perr *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	e := NewPanic(v)
+	if perr != nil {
+		*perr = e
+	}
+}
+
+// RecoverFunc recovers a panic and calls fn with an Erf object created from the panic value.
+// RecoverFunc must be deferred directly like Recover. RecoverFunc doesn't call fn if there is no panic.
+// See also NewPanic.
+func RecoverFunc(fn func(e *Erf)) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	e := NewPanic(v)
+	if fn != nil {
+		fn(e)
+	}
+}
+
+// NewPanic creates a new Erf object from the given panic value that returned by recover.
+// It must be called in the deferred function while panicking, to capture the stack of the panic.
+// The frames of the deferred functions and the runtime panic functions are dropped from the captured stack,
+// so the first frame of the stack trace is the function that panicked.
+// The panic value is the argument tagged with PanicTag. If the panic value is an error like runtime.Error,
+// Erf wraps it. It returns nil if v is nil.
+func NewPanic(v interface{}) *Erf {
+	if v == nil {
+		return nil
+	}
+	var e *Erf
+	if err, ok := v.(error); ok {
+		e = newf("panic: %w", err)
+	} else {
+		e = newf("panic: %v", v)
+	}
+	e.Attach(PanicTag)
+	e.initializePanic(DefaultCapture())
+	return e
+}
+
+func (e *Erf) initializePanic(capture Capture) {
+	if capture == CaptureNone {
+		return
+	}
+	pc, truncated := capturePC(DefaultPCSize, 3)
+	pc = trimPanic(pc)
+	if capture > 0 && int(capture) < len(pc) {
+		pc = pc[:capture]
+		truncated = true
+	}
+	e.pc, e.truncated = pc, truncated
+	e.captured = true
+}
+
+// trimPanic drops the frames above the panicking function, if pc has the frame of runtime.gopanic.
+func trimPanic(pc []uintptr) []uintptr {
+	for i := range pc {
+		if pcFunction(pc[i]) != "runtime.gopanic" {
+			continue
+		}
+		for i++; i < len(pc); i++ {
+			if !strings.HasPrefix(pcFunction(pc[i]), "runtime.") {
+				break
+			}
+		}
+		return pc[i:]
+	}
+	return pc
+}
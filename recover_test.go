@@ -0,0 +1,39 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/goinsane/erf"
+)
+
+func exampleRecoverIndex(i int) (err error) {
+	defer erf.Recover(&err)
+	a := []int{1, 2, 3}
+	_ = a[i]
+	return nil
+}
+
+func ExampleRecover() {
+	err := exampleRecoverIndex(5)
+	e := err.(*erf.Erf)
+
+	fmt.Println("the error message.")
+	fmt.Println(err)
+
+	fmt.Println("the panic value wraps runtime.Error.")
+	var re runtime.Error
+	fmt.Println(errors.As(err, &re), e.Tag(erf.PanicTag) == re)
+
+	fmt.Println("the first frame of the stack trace is the function that panicked.")
+	fmt.Println(e.StackTrace().Caller(0).Function)
+
+	// Output:
+	// the error message.
+	// panic: runtime error: index out of range [5] with length 3
+	// the panic value wraps runtime.Error.
+	// true true
+	// the first frame of the stack trace is the function that panicked.
+	// github.com/goinsane/erf_test.exampleRecoverIndex
+}
@@ -0,0 +1,107 @@
+package rpcerf
+
+import (
+	"encoding/json"
+	"errors"
+	"net/rpc"
+	"strings"
+
+	"github.com/goinsane/erf"
+)
+
+// ToServerError converts err to an error that can be returned from the methods of net/rpc services.
+// net/rpc sends only the error messages to the clients, so the message of the returned error is the JSON encoded
+// Error created by NewError. The clients can rebuild the error by using FromServerError or Client.
+// It returns nil if err is nil.
+func ToServerError(err error, opts *Options) error {
+	e := NewError(err, opts)
+	if e == nil {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return errors.New(string(data))
+}
+
+// FromServerError rebuilds an Erf object from the given rpc.ServerError that has the JSON encoded Error,
+// by using FromError. If err is an rpc.ServerError without the JSON encoded Error, it returns a new Erf object
+// that has the message of err without capturing the stack. Otherwise, it returns err.
+func FromServerError(err error) error {
+	var se rpc.ServerError
+	if !errors.As(err, &se) {
+		return err
+	}
+	if e := parseError(string(se)); e != nil {
+		return FromError(e)
+	}
+	return erf.CaptureNone.New(string(se))
+}
+
+func parseError(s string) *Error {
+	if !strings.HasPrefix(s, "{") {
+		return nil
+	}
+	var j struct {
+		Code    *int       `json:"code"`
+		Message string     `json:"message"`
+		Data    *ErrorData `json:"data"`
+	}
+	if json.Unmarshal([]byte(s), &j) != nil || j.Code == nil {
+		return nil
+	}
+	return &Error{
+		Code:    *j.Code,
+		Message: j.Message,
+		Data:    j.Data,
+	}
+}
+
+// Client is an rpc.Client that rebuilds the errors of the responses as Erf objects.
+type Client struct {
+	*rpc.Client
+}
+
+// NewClient returns a new Client that uses the given codec.
+func NewClient(c rpc.ClientCodec) *Client {
+	return &Client{
+		Client: rpc.NewClientWithCodec(c),
+	}
+}
+
+// Call is similar with rpc.Client.Call except that it rebuilds the rpc.ServerError by using FromServerError,
+// and returns an Erf object that wraps the rebuilt error and captures the stack of the caller.
+// The other errors like rpc.ErrShutdown are returned as is.
+func (c *Client) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	err := c.Client.Call(serviceMethod, args, reply)
+	var se rpc.ServerError
+	if !errors.As(err, &se) {
+		return err
+	}
+	return erf.WrapSkip(1, FromServerError(err))
+}
+
+// Go is similar with rpc.Client.Go except that it rebuilds the rpc.ServerError by using FromServerError.
+// Unlike Call, the rebuilt error doesn't capture the stack of the caller.
+// It panics if done is unbuffered.
+func (c *Client) Go(serviceMethod string, args interface{}, reply interface{}, done chan *rpc.Call) *rpc.Call {
+	if done == nil {
+		done = make(chan *rpc.Call, 10)
+	} else if cap(done) == 0 {
+		panic("done channel is unbuffered")
+	}
+	call := &rpc.Call{
+		ServiceMethod: serviceMethod,
+		Args:          args,
+		Reply:         reply,
+		Done:          done,
+	}
+	call2 := c.Client.Go(serviceMethod, args, reply, make(chan *rpc.Call, 1))
+	go func() {
+		<-call2.Done
+		call.Error = FromServerError(call2.Error)
+		call.Done <- call
+	}()
+	return call
+}
@@ -0,0 +1,193 @@
+// Package rpcerf provides JSON-RPC 2.0 and net/rpc error adapters for Erf.
+// It converts errors to JSON-RPC 2.0 error objects that carry the codes, the kinds, the tags and optionally
+// the error chains of Erf, and rebuilds Erf objects from them.
+package rpcerf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/goinsane/erf"
+)
+
+// The error codes defined by JSON-RPC 2.0.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000
+)
+
+// InternalMessage is the message of Error for the internal errors unless Options.Debug is enabled.
+const InternalMessage = "internal error"
+
+// Error is the error object defined by JSON-RPC 2.0.
+type Error struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+// ErrorData is the data of Error that carries the Code, the Kind and the tags of Erf.
+// The field Erf is the error chain encoded by erf.EncodeBase64.
+type ErrorData struct {
+	Code string                 `json:"code,omitempty"`
+	Kind string                 `json:"kind,omitempty"`
+	Tags map[string]interface{} `json:"tags,omitempty"`
+	Erf  string                 `json:"erf,omitempty"`
+}
+
+// Options is the options of NewError.
+// By default, the data of Error doesn't have any tag and the error chain, like httperf.
+type Options struct {
+	// Tags is the list of the tags that are public to the clients. Only these tags are included in the data of Error.
+	Tags []string
+
+	// AllTags enables including all tags in the error tree into the data of Error.
+	AllTags bool
+
+	// Chain enables encoding the error chain into the data of Error, except for the internal errors.
+	// The internal errors are the errors that don't have any erf.Kind or have the erf.Kind erf.KindInternal.
+	// The error chain has all tags and attributes of the errors in the chain.
+	Chain bool
+
+	// Debug enables encoding the error chain into the data of Error, also for the internal errors.
+	// It also enables sending the messages of the internal errors instead of InternalMessage.
+	// It must not be enabled in production.
+	Debug bool
+
+	// Frames enables encoding the stack traces in the error chain.
+	Frames bool
+}
+
+// Error is implementation of error. It returns the message of Error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError converts err to Error. It returns nil if err is nil. If opts is nil, the zero Options is used,
+// so the data of Error has only the code and the kind.
+// The message of Error is the error message, except for the internal errors that have InternalMessage as message
+// unless Debug is enabled, like the problem details of httperf that don't show the messages of the server errors.
+// The code of Error is the number of the nearest erf.Code in the error tree. If there is no erf.Code, the code is
+// mapped from the nearest erf.Kind: erf.KindInvalidArgument to CodeInvalidParams, erf.KindUnimplemented to
+// CodeMethodNotFound, erf.KindInternal to CodeInternalError, and the others to CodeServerError.
+// The values of the tags that can't be encoded with encoding/json are converted to strings by using
+// fmt.Sprintf("%v", value).
+func NewError(err error, opts *Options) *Error {
+	if err == nil {
+		return nil
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	e := &Error{
+		Code:    codeOf(err),
+		Message: err.Error(),
+	}
+	if !opts.Debug && isInternal(err) {
+		e.Message = InternalMessage
+	}
+	data := &ErrorData{}
+	if code := erf.CodeOf(err); code != nil {
+		data.Code = code.Name()
+	}
+	if kind := erf.KindOf(err); kind != nil {
+		data.Kind = kind.Name()
+	}
+	if opts.AllTags {
+		for _, kv := range erf.FlattenTags(err) {
+			data.setTag(kv.Key, kv.Value)
+		}
+	} else {
+		for _, tag := range opts.Tags {
+			if value, ok := erf.LookupTag(err, tag); ok {
+				data.setTag(tag, value)
+			}
+		}
+	}
+	if opts.Debug || (opts.Chain && !isInternal(err)) {
+		data.Erf, _ = erf.EncodeBase64(err, &erf.CodecOptions{
+			Frames: opts.Frames,
+		})
+	}
+	if data.Code != "" || data.Kind != "" || data.Tags != nil || data.Erf != "" {
+		e.Data = data
+	}
+	return e
+}
+
+// FromError rebuilds an Erf object from the given Error, and returns it as the error interface.
+// It returns nil if e is nil. If the data of Error has the error chain, the decoded Erf object is returned,
+// so errors.Is matches the registered sentinel errors, codes and kinds of erf. Otherwise, FromError returns a new Erf
+// object that has the message of Error without capturing the stack, the registered erf.Code with the same number
+// and name, the registered erf.Kind with the same name and the tags as attributes sorted by their names.
+func FromError(e *Error) error {
+	if e == nil {
+		return nil
+	}
+	if e.Data != nil && e.Data.Erf != "" {
+		if e2, err := erf.DecodeBase64(e.Data.Erf); err == nil {
+			return e2
+		}
+	}
+	e2 := erf.CaptureNone.New(e.Message)
+	if e.Data == nil {
+		return e2
+	}
+	if code := erf.LookupCode(e.Code); code != nil && code.Name() == e.Data.Code {
+		e2.WithCode(code)
+	}
+	if kind := erf.LookupKind(e.Data.Kind); kind != nil {
+		e2.WithKind(kind)
+	}
+	keys := make([]string, 0, len(e.Data.Tags))
+	for key := range e.Data.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_ = e2.TryWith(key, e.Data.Tags[key])
+	}
+	return e2
+}
+
+func codeOf(err error) int {
+	if code := erf.CodeOf(err); code != nil {
+		return code.Number()
+	}
+	kind := erf.KindOf(err)
+	switch {
+	case kind == nil:
+		return CodeServerError
+	case errors.Is(kind, erf.KindInvalidArgument):
+		return CodeInvalidParams
+	case errors.Is(kind, erf.KindUnimplemented):
+		return CodeMethodNotFound
+	case errors.Is(kind, erf.KindInternal):
+		return CodeInternalError
+	}
+	return CodeServerError
+}
+
+func isInternal(err error) bool {
+	kind := erf.KindOf(err)
+	return kind == nil || errors.Is(kind, erf.KindInternal)
+}
+
+func (d *ErrorData) setTag(tag string, value interface{}) {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	if _, err := json.Marshal(value); err != nil {
+		value = fmt.Sprintf("%v", value)
+	}
+	if d.Tags == nil {
+		d.Tags = make(map[string]interface{})
+	}
+	d.Tags[tag] = value
+}
@@ -0,0 +1,102 @@
+package rpcerf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+
+	"github.com/goinsane/erf"
+	"github.com/goinsane/erf/rpcerf"
+)
+
+var codeExampleUserNotFound = erf.RegisterCode(2001, "example_rpc_user_not_found", erf.KindNotFound)
+
+func ExampleNewError() {
+	err := erf.Newf("user %q not found", "alice").Attach("name").WithCode(codeExampleUserNotFound)
+
+	data, _ := json.Marshal(rpcerf.NewError(err, nil))
+	fmt.Printf("%s\n", data)
+
+	data, _ = json.Marshal(rpcerf.NewError(err, &rpcerf.Options{
+		Tags: []string{"name"},
+	}))
+	fmt.Printf("%s\n", data)
+
+	var e rpcerf.Error
+	_ = json.Unmarshal(data, &e)
+	err2 := rpcerf.FromError(&e)
+	fmt.Println(err2)
+	fmt.Println(errors.Is(err2, codeExampleUserNotFound), errors.Is(err2, erf.KindNotFound))
+	fmt.Println(erf.LookupTag(err2, "name"))
+
+	// Output:
+	// {"code":2001,"message":"user \"alice\" not found","data":{"code":"example_rpc_user_not_found","kind":"not_found"}}
+	// {"code":2001,"message":"user \"alice\" not found","data":{"code":"example_rpc_user_not_found","kind":"not_found","tags":{"name":"alice"}}}
+	// user "alice" not found
+	// true true
+	// alice true
+}
+
+type ExampleService struct{}
+
+func (s *ExampleService) Find(name string, reply *string) error {
+	if name == "alice" {
+		err := erf.Newf("user %q not found", name).Attach("name").WithCode(codeExampleUserNotFound)
+		return rpcerf.ToServerError(err, &rpcerf.Options{
+			Chain: true,
+		})
+	}
+	if name == "bob" {
+		err := erf.Newf("database of user %q is down", name).Attach("name")
+		return rpcerf.ToServerError(err, &rpcerf.Options{
+			Chain: true,
+		})
+	}
+	return errors.New("an example plain error")
+}
+
+func ExampleNewClient() {
+	srv := rpc.NewServer()
+	_ = srv.Register(&ExampleService{})
+	serverConn, clientConn := net.Pipe()
+	go srv.ServeCodec(jsonrpc.NewServerCodec(serverConn))
+
+	client := rpcerf.NewClient(jsonrpc.NewClientCodec(clientConn))
+	defer client.Close()
+
+	var reply string
+	err := client.Call("ExampleService.Find", "alice", &reply)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, codeExampleUserNotFound), errors.Is(err, erf.KindNotFound))
+	fmt.Println(erf.LookupTag(err, "name"))
+
+	err = client.Call("ExampleService.Find", "bob", &reply)
+	fmt.Println(err)
+	fmt.Println(erf.LookupTag(err, "name"))
+
+	err = client.Call("ExampleService.Find", "carol", &reply)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, erf.KindNotFound))
+
+	call := <-client.Go("ExampleService.Find", "alice", &reply, nil).Done
+	fmt.Println(call.Error)
+	fmt.Println(errors.Is(call.Error, codeExampleUserNotFound), errors.Is(call.Error, erf.KindNotFound))
+
+	err = rpcerf.FromServerError(rpc.ServerError(`{"code":0,"message":"an example error with code 0"}`))
+	fmt.Println(err)
+
+	// Output:
+	// user "alice" not found
+	// true true
+	// alice true
+	// internal error
+	// <nil> false
+	// an example plain error
+	// false
+	// user "alice" not found
+	// true true
+	// an example error with code 0
+}
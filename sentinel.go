@@ -0,0 +1,91 @@
+package erf
+
+// NewSentinel creates a new Erf object with the given text without capturing the stack.
+// It is useful to declare package-level sentinel errors, the stack of the package initialization is useless.
+// The sentinel errors should be returned by using Here or At to get the stack of the call site, like the following:
+// 	var ErrNotFound = erf.NewSentinel("not found")
+// 	func Foo() error {
+// 		...
+// 		return ErrNotFound.Here()
+// 	}
+func NewSentinel(text string) *Erf {
+	return newText(text)
+}
+
+// Here returns a copy of Erf that has the stack of the caller.
+// The copy matches Erf by using errors.Is, unlike Wrap it doesn't wrap Erf.
+// Only the error, the arguments, the tags, the attributes, the diagnostics, the Code and the Kind are kept in the copy,
+// the return trace and the spawn points aren't kept.
+func (e *Erf) Here() *Erf {
+	return e.at(0)
+}
+
+// At is similar with Here except that it skips the given number of stack frames above the caller.
+// If skip is 0, At is same with Here.
+func (e *Erf) At(skip int) *Erf {
+	return e.at(skip)
+}
+
+func (e *Erf) at(skip int) *Erf {
+	e2 := &Erf{
+		err:        e.err,
+		format:     e.format,
+		args:       e.args,
+		tags:       e.tags,
+		tagIndexes: e.tagIndexes,
+		attrs:      e.attrs,
+		diags:      e.diags,
+		code:       e.code,
+		kind:       e.kind,
+		origin:     e.origin,
+	}
+	if e2.origin == nil {
+		e2.origin = e
+	}
+	e2.initialize(5+skip, DefaultCapture())
+	return e2
+}
+
+// RegisterSentinel registers the given sentinel error with the given unique name, and returns the sentinel error.
+// The registered sentinel errors and their copies created by Here or At are encoded with their names by
+// EncodeBinary, and the decoded errors match the registered sentinel errors by using errors.Is.
+// It panics if the name is empty, e is nil, or the name or e is already registered.
+func RegisterSentinel(name string, e *Erf) *Erf {
+	if name == "" {
+		panic("sentinel name is empty")
+	}
+	if e == nil {
+		panic("sentinel is nil")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.sentinels[name]; ok {
+		panic("sentinel already registered")
+	}
+	if _, ok := registry.sentinelNames[e]; ok {
+		panic("sentinel already registered")
+	}
+	registry.sentinels[name] = e
+	registry.sentinelNames[e] = name
+	return e
+}
+
+// LookupSentinel returns the registered sentinel error with the given name. It returns nil if it is not found.
+func LookupSentinel(name string) *Erf {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.sentinels[name]
+}
+
+// sentinelName returns the registered name of e or the original Erf of e. It returns "" if it isn't registered.
+func (e *Erf) sentinelName() string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if name, ok := registry.sentinelNames[e]; ok {
+		return name
+	}
+	if e.origin != nil {
+		return registry.sentinelNames[e.origin]
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+var errExampleNotFound = erf.NewSentinel("not found")
+
+func ExampleErf_Here() {
+	err := errExampleNotFound.Here()
+
+	fmt.Println(errors.Is(err, errExampleNotFound), err == errExampleNotFound)
+	fmt.Println(errExampleNotFound.Captured(), err.Captured())
+	fmt.Println(errors.Is(erf.Wrap(err), errExampleNotFound), errors.Is(err.Here(), errExampleNotFound))
+
+	// Output:
+	// true false
+	// false true
+	// true true
+}
+
+func exampleNotFoundAt() *erf.Erf {
+	return errExampleNotFound.At(1)
+}
+
+func ExampleErf_At() {
+	err := exampleNotFoundAt()
+
+	fmt.Println(errors.Is(err, errExampleNotFound))
+	fmt.Println(err.StackTrace().Caller(0).Function)
+
+	// Output:
+	// true
+	// github.com/goinsane/erf_test.ExampleErf_At
+}
@@ -0,0 +1,151 @@
+package erf
+
+import (
+	"context"
+)
+
+// spawn stores the stack of a spawn point where a goroutine was started.
+type spawn struct {
+	pc        []uintptr
+	truncated bool
+	st        *StackTrace
+	parent    *spawn
+}
+
+type spawnContextKey struct{}
+
+func newSpawn(parent *spawn, skip int) *spawn {
+	capture := DefaultCapture()
+	if capture == CaptureNone {
+		return parent
+	}
+	size := DefaultPCSize
+	if capture > 0 && int(capture) < size {
+		size = int(capture)
+	}
+	s := &spawn{
+		parent: parent,
+	}
+	s.pc, s.truncated = capturePC(size, skip)
+	return s
+}
+
+func (s *spawn) stackTrace() *StackTrace {
+	if s.st != nil {
+		return s.st
+	}
+	t := NewStackTrace(s.pc...)
+	t.truncated = s.truncated
+	return t
+}
+
+func spawnFromContext(ctx context.Context) *spawn {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spawnContextKey{}).(*spawn)
+	return s
+}
+
+// WithSpawn returns a copy of ctx that stores the stack of the caller as a spawn point.
+// It should be called before starting a goroutine, and the returned context should be passed to the goroutine.
+// The errors created in the goroutine by using NewCtx, NewfCtx, ErrorfCtx or WrapCtx with the returned context are
+// linked to the spawn points, and the other errors returned from the goroutine can be linked by using Spawned.
+// If ctx already has spawn points, the new spawn point is linked to them.
+// WithSpawn captures the stack by using the default Capture, if it is CaptureNone, WithSpawn returns ctx.
+func WithSpawn(ctx context.Context) context.Context {
+	s := newSpawn(spawnFromContext(ctx), 4)
+	if s == nil || s == spawnFromContext(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, spawnContextKey{}, s)
+}
+
+// Spawned links the spawn points stored in ctx by WithSpawn to err, and returns the result as the error interface.
+// If err is an Erf, Spawned returns a copy of err that has the spawn points, and the copy matches err
+// by using errors.Is. Otherwise, Spawned wraps err into a new Erf without capturing the stack.
+// Spawned doesn't affect if err is nil, ctx doesn't have any spawn point or err already has spawn points.
+func Spawned(ctx context.Context, err error) error {
+	return linkSpawn(err, spawnFromContext(ctx))
+}
+
+func linkSpawn(err error, s *spawn) error {
+	if err == nil || s == nil {
+		return err
+	}
+	e, ok := err.(*Erf)
+	if !ok {
+		e = newWrap(err)
+		e.spawn = s
+		return e
+	}
+	if e.spawn != nil {
+		return e
+	}
+	e2 := e.clone()
+	e2.spawn = s
+	return e2
+}
+
+// NewCtx is similar with New except that it links the spawn points stored in ctx by WithSpawn to the new Erf.
+func NewCtx(ctx context.Context, text string) *Erf {
+	e := newText(text)
+	e.initialize(4, DefaultCapture())
+	e.spawn = spawnFromContext(ctx)
+	return e
+}
+
+// NewfCtx is similar with Newf except that it links the spawn points stored in ctx by WithSpawn to the new Erf.
+func NewfCtx(ctx context.Context, format string, args ...interface{}) *Erf {
+	e := newf(format, args...)
+	e.initialize(4, DefaultCapture())
+	e.spawn = spawnFromContext(ctx)
+	return e
+}
+
+// ErrorfCtx is similar with Errorf except that it links the spawn points stored in ctx by WithSpawn to the new Erf.
+func ErrorfCtx(ctx context.Context, format string, a ...interface{}) error {
+	e := newf(format, a...)
+	e.initialize(4, DefaultCapture())
+	e.spawn = spawnFromContext(ctx)
+	return e
+}
+
+// WrapCtx is similar with Wrap except that it links the spawn points stored in ctx by WithSpawn to the new Erf.
+func WrapCtx(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := newWrap(err)
+	e.initialize(4, DefaultCapture())
+	e.spawn = spawnFromContext(ctx)
+	return e
+}
+
+// SpawnTraces returns the stack traces of the spawn points that linked to Erf, the first StackTrace is the nearest
+// spawn point. It returns nil if Erf doesn't have any spawn point.
+func (e *Erf) SpawnTraces() []*StackTrace {
+	var result []*StackTrace
+	for s := e.spawn; s != nil; s = s.parent {
+		result = append(result, s.stackTrace())
+	}
+	return result
+}
+
+// GoContext is similar with Go except that it calls fn with a copy of ctx that stores the stack of the caller as
+// a spawn point like WithSpawn, and links the spawn points to the result of fn like Spawned.
+// Only the result of fn is linked, not the errors that fn wraps.
+func GoContext(ctx context.Context, r Reporter, fn func(ctx context.Context) error) {
+	if r == nil {
+		r = LogReporter(nil)
+	}
+	s := newSpawn(spawnFromContext(ctx), 4)
+	if s != nil && s != spawnFromContext(ctx) {
+		ctx = context.WithValue(ctx, spawnContextKey{}, s)
+	}
+	go func() {
+		r.Report(linkSpawn(call(func() error {
+			return fn(ctx)
+		}), s))
+	}()
+}
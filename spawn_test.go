@@ -0,0 +1,84 @@
+package erf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+func exampleSpawnHandler(ch chan error) {
+	erf.GoContext(context.Background(), erf.ChanReporter(ch), func(ctx context.Context) error {
+		return erf.New("an example erf error in the goroutine")
+	})
+}
+
+func ExampleGoContext() {
+	ch := make(chan error, 1)
+	exampleSpawnHandler(ch)
+	err := <-ch
+
+	fmt.Println("the goroutine was spawned by the function.")
+	fmt.Println(err.(*erf.Erf).SpawnTraces()[0].Caller(0).Function)
+
+	// Output:
+	// the goroutine was spawned by the function.
+	// github.com/goinsane/erf_test.exampleSpawnHandler
+}
+
+var errExampleSpawn = erf.NewSentinel("an example erf error in the goroutine")
+
+func exampleSpawnWorker(ctx context.Context, ch chan error) {
+	ch <- erf.NewfCtx(ctx, "an example erf error on %q", "foo")
+	e := errExampleSpawn.Here()
+	ch <- e
+	ch <- erf.Spawned(ctx, e)
+}
+
+func ExampleWithSpawn() {
+	erf.SetDefaultCapture(erf.CaptureCaller)
+	defer erf.SetDefaultCapture(erf.CaptureFull)
+
+	ch := make(chan error, 3)
+	go exampleSpawnWorker(erf.WithSpawn(context.Background()), ch)
+
+	err := <-ch
+	fmt.Println("the error created by using the context is linked to the spawn point.")
+	printLines(fmt.Sprintf("%#x", err))
+
+	err = <-ch
+	fmt.Println("the error created without the context doesn't have spawn points.")
+	fmt.Println(len(err.(*erf.Erf).SpawnTraces()))
+
+	err = <-ch
+	fmt.Println("the error passed to Spawned is linked to the spawn point, and it matches the original error.")
+	fmt.Println(err.(*erf.Erf).SpawnTraces()[0].Caller(0).Function)
+	fmt.Println(errors.Is(err, errExampleSpawn), erf.Spawned(context.Background(), err) == err)
+
+	ctx := erf.WithSpawn(context.Background())
+	fmt.Println("the other constructors that use the context.")
+	fmt.Println(len(erf.NewCtx(ctx, "an example erf error").SpawnTraces()),
+		len(erf.ErrorfCtx(ctx, "an example erf error: %w", err).(*erf.Erf).SpawnTraces()),
+		len(erf.WrapCtx(ctx, err).(*erf.Erf).SpawnTraces()),
+		erf.WrapCtx(ctx, nil) == nil)
+
+	// Output:
+	// the error created by using the context is linked to the spawn point.
+	// 	an example erf error on "foo"
+	// github.com/goinsane/erf_test.exampleSpawnWorker(0x?)
+	// 	spawn_test.go:? +0x?
+	// * stack trace truncated
+	// > spawned at
+	// github.com/goinsane/erf_test.ExampleWithSpawn(0x?)
+	// 	spawn_test.go:? +0x?
+	// * stack trace truncated
+	//
+	// the error created without the context doesn't have spawn points.
+	// 0
+	// the error passed to Spawned is linked to the spawn point, and it matches the original error.
+	// github.com/goinsane/erf_test.ExampleWithSpawn
+	// true true
+	// the other constructors that use the context.
+	// 1 1 1 true
+}
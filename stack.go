@@ -15,7 +15,10 @@ type StackCaller struct {
 // String is implementation of fmt.Stringer.
 // It is synonym with fmt.Sprintf("%s", c).
 func (c StackCaller) String() string {
-	return fmt.Sprintf("%s", c)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	c.format(buf, false, false, '\t', 0, 1)
+	return buf.String()
 }
 
 // Format is implementation of fmt.Formatter.
@@ -39,56 +42,76 @@ func (c StackCaller) String() string {
 // 	%#4.3s   same with '%#s', padding 4, indent 3.
 // 	% #4.3s  same with '% #s', padding 4, indent 3.
 func (c StackCaller) Format(f fmt.State, verb rune) {
-	buf := bytes.NewBuffer(make([]byte, 0, 4096))
 	switch verb {
 	case 's', 'v':
-		fn := "???"
-		if c.Function != "" {
-			fn = trimSrcPath(c.Function)
-		}
-		extended := f.Flag('+') || f.Flag(' ') || f.Flag('#')
-		if !extended {
-			buf.WriteString(fmt.Sprintf("%s(%#x)", fn, c.Entry))
-			break
-		}
-		pad, wid, prec := getPadWidPrec(f)
-		padding, indent := bytes.Repeat([]byte{pad}, wid), bytes.Repeat([]byte{pad}, prec)
-		buf.Write(padding)
-		buf.WriteString(fmt.Sprintf("%s(%#x)", fn, c.Entry))
-		buf.WriteRune('\n')
-		buf.Write(padding)
-		buf.Write(indent)
-		file, line := "???", 0
-		if c.File != "" {
-			file = trimSrcPath(c.File)
-			if f.Flag('#') {
-				file = trimDirs(file)
-			}
-		}
-		if c.Line > 0 {
-			line = c.Line
-		}
-		buf.WriteString(fmt.Sprintf("%s:%d +%#x", file, line, c.PC-c.Entry))
 	default:
 		return
 	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	pad, wid, prec := getPadWidPrec(f)
+	c.format(buf, f.Flag('+') || f.Flag(' ') || f.Flag('#'), f.Flag('#'), pad, wid, prec)
 	_, _ = f.Write(buf.Bytes())
 }
 
+func (c StackCaller) format(buf *bytes.Buffer, extended, fileName bool, pad byte, wid, prec int) {
+	fn := "???"
+	if c.Function != "" {
+		fn = trimSrcPath(c.Function)
+	}
+	if !extended {
+		buf.WriteString(fn)
+		buf.WriteRune('(')
+		writeHex(buf, c.Entry)
+		buf.WriteRune(')')
+		return
+	}
+	writePadding(buf, pad, wid)
+	buf.WriteString(fn)
+	buf.WriteRune('(')
+	writeHex(buf, c.Entry)
+	buf.WriteRune(')')
+	buf.WriteRune('\n')
+	writePadding(buf, pad, wid+prec)
+	file, line := "???", 0
+	if c.File != "" {
+		file = trimSrcPath(c.File)
+		if fileName {
+			file = trimDirs(file)
+		}
+	}
+	if c.Line > 0 {
+		line = c.Line
+	}
+	buf.WriteString(file)
+	buf.WriteRune(':')
+	writeInt(buf, line)
+	buf.WriteString(" +")
+	writeHex(buf, c.PC-c.Entry)
+}
+
 // StackTrace stores the information of stack trace.
+// StackTrace is live if it is built from program counters, otherwise it is synthetic.
 type StackTrace struct {
-	pc      []uintptr
-	callers []StackCaller
+	pc        []uintptr
+	callers   []StackCaller
+	synthetic bool
+	truncated bool
 }
 
-// NewStackTrace creates a new StackTrace object.
+// NewStackTrace creates a new live StackTrace object from the given program counters.
+// It resolves the program counters by using the symbol cache, unless the symbol cache is disabled.
 func NewStackTrace(pc ...uintptr) *StackTrace {
 	t := &StackTrace{
 		pc:      make([]uintptr, len(pc)),
 		callers: make([]StackCaller, 0, len(pc)),
 	}
 	copy(t.pc, pc)
-	if len(t.pc) > 0 {
+	if len(t.pc) > 0 && symbolCache.enabled() {
+		for _, p := range t.pc {
+			t.callers = append(t.callers, symbolCache.resolve(p)...)
+		}
+	} else if len(t.pc) > 0 {
 		frames := runtime.CallersFrames(t.pc)
 		for {
 			frame, more := frames.Next()
@@ -104,14 +127,28 @@ func NewStackTrace(pc ...uintptr) *StackTrace {
 	return t
 }
 
+// NewStackTraceFromCallers creates a new synthetic StackTrace object from the given pre-resolved callers.
+// It is useful to represent the stack traces that come from a file, the network or a parsed panic.
+func NewStackTraceFromCallers(callers ...StackCaller) *StackTrace {
+	t := &StackTrace{
+		pc:        nil,
+		callers:   make([]StackCaller, len(callers)),
+		synthetic: true,
+	}
+	copy(t.callers, callers)
+	return t
+}
+
 // Duplicate duplicates the StackTrace object.
 func (t *StackTrace) Duplicate() *StackTrace {
 	if t == nil {
 		return nil
 	}
 	t2 := &StackTrace{
-		pc:      make([]uintptr, len(t.pc), cap(t.pc)),
-		callers: make([]StackCaller, len(t.callers), cap(t.callers)),
+		pc:        make([]uintptr, len(t.pc), cap(t.pc)),
+		callers:   make([]StackCaller, len(t.callers), cap(t.callers)),
+		synthetic: t.synthetic,
+		truncated: t.truncated,
 	}
 	copy(t2.pc, t.pc)
 	copy(t2.callers, t.callers)
@@ -121,37 +158,74 @@ func (t *StackTrace) Duplicate() *StackTrace {
 // String is implementation of fmt.Stringer.
 // It is synonym with fmt.Sprintf("%s", t).
 func (t *StackTrace) String() string {
-	return fmt.Sprintf("%s", t)
+	if t == nil {
+		return ""
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	t.format(buf, false, false, '\t', 0, 1)
+	return buf.String()
 }
 
 // Format is implementation of fmt.Formatter.
 // Format lists all StackCaller's in StackTrace line by line with given format.
+// If StackTrace is truncated, Format appends the line "* stack trace truncated" by using padding.
 func (t *StackTrace) Format(f fmt.State, verb rune) {
-	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	if t == nil {
+		return
+	}
 	switch verb {
 	case 's', 'v':
-		format := "%"
-		for _, r := range []rune{'+', ' ', '#'} {
-			if f.Flag(int(r)) {
-				format += string(r)
-			}
-		}
-		_, wid, prec := getPadWidPrec(f)
-		format += fmt.Sprintf("%d.%ds", wid, prec)
-		for i, c := range t.callers {
-			if i > 0 {
-				buf.WriteRune('\n')
-			}
-			buf.WriteString(fmt.Sprintf(format, c))
-		}
 	default:
 		return
 	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	pad, wid, prec := getPadWidPrec(f)
+	t.format(buf, f.Flag('+') || f.Flag(' ') || f.Flag('#'), f.Flag('#'), pad, wid, prec)
 	_, _ = f.Write(buf.Bytes())
 }
 
-// PC returns program counters.
+func (t *StackTrace) format(buf *bytes.Buffer, extended, fileName bool, pad byte, wid, prec int) {
+	for i, c := range t.callers {
+		if i > 0 {
+			buf.WriteRune('\n')
+		}
+		c.format(buf, extended, fileName, pad, wid, prec)
+	}
+	if t.truncated {
+		if len(t.callers) > 0 {
+			buf.WriteRune('\n')
+		}
+		if extended {
+			writePadding(buf, pad, wid)
+		}
+		buf.WriteString("* stack trace truncated")
+	}
+}
+
+// Synthetic returns true if StackTrace was created by NewStackTraceFromCallers or decoded, otherwise false.
+// The synthetic StackTrace doesn't have program counters.
+func (t *StackTrace) Synthetic() bool {
+	if t == nil {
+		return false
+	}
+	return t.synthetic
+}
+
+// Truncated returns true if the stack had more frames than StackTrace has, otherwise false.
+func (t *StackTrace) Truncated() bool {
+	if t == nil {
+		return false
+	}
+	return t.truncated
+}
+
+// PC returns program counters. It returns an empty slice if StackTrace is synthetic.
 func (t *StackTrace) PC() []uintptr {
+	if t == nil {
+		return nil
+	}
 	result := make([]uintptr, len(t.pc))
 	copy(result, t.pc)
 	return result
@@ -159,7 +233,7 @@ func (t *StackTrace) PC() []uintptr {
 
 // Caller returns a StackCaller on the given index. It panics if index is out of range.
 func (t *StackTrace) Caller(index int) StackCaller {
-	if index < 0 || index >= len(t.callers) {
+	if index < 0 || index >= t.Len() {
 		panic("index out of range")
 	}
 	return t.callers[index]
@@ -167,5 +241,8 @@ func (t *StackTrace) Caller(index int) StackCaller {
 
 // Len returns the length of the length of all Caller's.
 func (t *StackTrace) Len() int {
+	if t == nil {
+		return 0
+	}
 	return len(t.callers)
 }
@@ -2,6 +2,8 @@ package erf_test
 
 import (
 	"fmt"
+	"runtime"
+	"testing"
 
 	"github.com/goinsane/erf"
 )
@@ -51,3 +53,51 @@ func ExampleStackTrace() {
 	fmt.Println("show file path, line and pc. padding char ' ', default padding 0, default indent 2.")
 	fmt.Printf("% s\n\n", st)
 }
+
+func ExampleNewStackTraceFromCallers() {
+	st := erf.NewStackTraceFromCallers(
+		erf.StackCaller{Frame: runtime.Frame{Function: "main.Foo", File: "/src/main.go", Line: 21, PC: 0x53}},
+		erf.StackCaller{Frame: runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 42, PC: 0x1f}},
+	)
+
+	fmt.Println("synthetic")
+	fmt.Printf("%v\n\n", st.Synthetic())
+
+	fmt.Println("show file path, line and pc. padding char '\\t', default padding 0, default indent 1.")
+	fmt.Printf("%+s\n\n", st)
+
+	// Output:
+	// synthetic
+	// true
+	//
+	// show file path, line and pc. padding char '\t', default padding 0, default indent 1.
+	// main.Foo(0x0)
+	// 	/src/main.go:21 +0x53
+	// main.main(0x0)
+	// 	/src/main.go:42 +0x1f
+}
+
+func ExampleGetSymbolCacheStats() {
+	erf.SetSymbolCacheSize(erf.DefaultSymbolCacheSize)
+	e := erf.New("an example erf error")
+
+	_ = erf.NewStackTrace(e.PC()...)
+	stats1 := erf.GetSymbolCacheStats()
+	_ = erf.NewStackTrace(e.PC()...)
+	stats2 := erf.GetSymbolCacheStats()
+
+	fmt.Println("the second StackTrace is resolved from the symbol cache.")
+	fmt.Println(stats2.Hits-stats1.Hits == uint64(e.PCLen()), stats2.Misses == stats1.Misses)
+
+	// Output:
+	// the second StackTrace is resolved from the symbol cache.
+	// true true
+}
+
+func BenchmarkNewStackTrace(b *testing.B) {
+	pc := erf.New("an example erf error").PC()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = erf.NewStackTrace(pc...)
+	}
+}
@@ -0,0 +1,124 @@
+package erf
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSymbolCacheSize is the default maximum number of program counters in the symbol cache.
+const DefaultSymbolCacheSize = 4096
+
+// SymbolCacheStats stores the statistics of the symbol cache.
+type SymbolCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Len    int
+	Size   int
+}
+
+var (
+	symbolCacheHits   uint64
+	symbolCacheMisses uint64
+	symbolCache       = newSymCache(DefaultSymbolCacheSize)
+)
+
+// GetSymbolCacheStats returns the statistics of the symbol cache.
+func GetSymbolCacheStats() SymbolCacheStats {
+	symbolCache.mu.RLock()
+	defer symbolCache.mu.RUnlock()
+	return SymbolCacheStats{
+		Hits:   atomic.LoadUint64(&symbolCacheHits),
+		Misses: atomic.LoadUint64(&symbolCacheMisses),
+		Len:    len(symbolCache.callers),
+		Size:   symbolCache.size,
+	}
+}
+
+// SetSymbolCacheSize sets the maximum number of program counters in the symbol cache, and clears the cache.
+// If size is 0 or negative, the symbol cache is disabled.
+// The symbol cache stores the StackCaller's resolved from program counters by NewStackTrace,
+// including the callers of inlined functions.
+func SetSymbolCacheSize(size int) {
+	symbolCache.mu.Lock()
+	defer symbolCache.mu.Unlock()
+	symbolCache.reset(size)
+}
+
+type symCache struct {
+	mu      sync.RWMutex
+	size    int
+	callers map[uintptr][]StackCaller
+	keys    []uintptr
+	next    int
+}
+
+func newSymCache(size int) *symCache {
+	c := &symCache{}
+	c.reset(size)
+	return c
+}
+
+func (c *symCache) reset(size int) {
+	if size < 0 {
+		size = 0
+	}
+	c.size = size
+	c.callers = make(map[uintptr][]StackCaller)
+	c.keys = make([]uintptr, 0, size)
+	c.next = 0
+}
+
+func (c *symCache) enabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.size > 0
+}
+
+func (c *symCache) get(pc uintptr) ([]StackCaller, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	callers, ok := c.callers[pc]
+	return callers, ok
+}
+
+func (c *symCache) put(pc uintptr, callers []StackCaller) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	if _, ok := c.callers[pc]; ok {
+		return
+	}
+	if len(c.keys) < c.size {
+		c.keys = append(c.keys, pc)
+	} else {
+		delete(c.callers, c.keys[c.next])
+		c.keys[c.next] = pc
+		c.next = (c.next + 1) % c.size
+	}
+	c.callers[pc] = callers
+}
+
+// resolve returns StackCaller's of the given program counter by using the symbol cache.
+func (c *symCache) resolve(pc uintptr) []StackCaller {
+	if callers, ok := c.get(pc); ok {
+		atomic.AddUint64(&symbolCacheHits, 1)
+		return callers
+	}
+	atomic.AddUint64(&symbolCacheMisses, 1)
+	callers := make([]StackCaller, 0, 1)
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		callers = append(callers, StackCaller{
+			Frame: frame,
+		})
+		if !more {
+			break
+		}
+	}
+	c.put(pc, callers)
+	return callers
+}
@@ -0,0 +1,74 @@
+package erf
+
+type tagged interface {
+	Tags() []string
+	Tag(tag string) interface{}
+}
+
+// LookupTag finds the nearest value of the given tag in the error tree of err, and reports whether the tag is found.
+// The errors are visited in the same order with Walk, so the outer errors take precedence over the wrapped errors,
+// and the earlier branches take precedence over the later branches.
+// Every error in the tree that has the methods Tags and Tag is queried, like the Erf objects, the TracedError's and
+// the error types embedding Erf. The other errors in the tree are passed through.
+func LookupTag(err error, tag string) (value interface{}, ok bool) {
+	Walk(err, func(err error, depth int) bool {
+		if ok {
+			return false
+		}
+		value, ok = lookupTag(err, tag)
+		return !ok
+	})
+	return value, ok
+}
+
+// LookupTagAll returns all values of the given tag in the error tree of err, in the same order with LookupTag.
+// It returns nil if the tag is not found.
+func LookupTagAll(err error, tag string) []interface{} {
+	var result []interface{}
+	Walk(err, func(err error, depth int) bool {
+		if value, ok := lookupTag(err, tag); ok {
+			result = append(result, value)
+		}
+		return true
+	})
+	return result
+}
+
+// FlattenTags returns all tags in the error tree of err as a list of key/value pairs, each tag appears once.
+// The value of a tag is the nearest value like LookupTag, and the tags are sorted by the order that
+// they are found first in the same order with LookupTag. It returns nil if there is no tag.
+func FlattenTags(err error) []KeyValue {
+	var result []KeyValue
+	found := make(map[string]struct{})
+	Walk(err, func(err error, depth int) bool {
+		e, ok := err.(tagged)
+		if !ok {
+			return true
+		}
+		for _, tag := range e.Tags() {
+			if _, ok := found[tag]; ok {
+				continue
+			}
+			found[tag] = struct{}{}
+			result = append(result, KeyValue{
+				Key:   tag,
+				Value: e.Tag(tag),
+			})
+		}
+		return true
+	})
+	return result
+}
+
+func lookupTag(err error, tag string) (interface{}, bool) {
+	e, ok := err.(tagged)
+	if !ok {
+		return nil, false
+	}
+	for _, t := range e.Tags() {
+		if t == tag {
+			return e.Tag(tag), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,32 @@
+package erf_test
+
+import (
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+type exampleTaggedError struct {
+	*erf.Erf
+}
+
+func ExampleLookupTag() {
+	e := &exampleTaggedError{
+		Erf: erf.Newf("invalid argument %q", "x").Attach("name").With("op", "parse"),
+	}
+	err := erf.Wrap(fmt.Errorf("we have an example error: %w", e))
+	err = erf.Wrap(err).(*erf.Erf).With("op", "handle")
+
+	fmt.Println(erf.LookupTag(err, "name"))
+	fmt.Println(erf.LookupTag(err, "op"))
+	fmt.Println(erf.LookupTag(err, "unknown"))
+	fmt.Println(erf.LookupTagAll(err, "op"))
+	fmt.Println(erf.FlattenTags(err))
+
+	// Output:
+	// x true
+	// handle true
+	// <nil> false
+	// [handle parse]
+	// [{op handle} {name x}]
+}
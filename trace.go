@@ -0,0 +1,99 @@
+package erf
+
+import (
+	"runtime"
+)
+
+// Trace appends the frame of the caller to the return trace of err, and returns the result as the error interface.
+// The return trace records the path that the error travels back through the functions, frame by frame.
+// If err is an Erf, Trace returns a copy of err that has the appended return trace, and the copy matches err
+// by using errors.Is. Otherwise, Trace wraps err into a new Erf that doesn't capture the stack, and
+// the caller frame is recorded as the first frame of its return trace.
+// Trace returns nil if err is nil.
+func Trace(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) < 1 {
+		return err
+	}
+	return trace(err, pc[0])
+}
+
+// Tracep is similar with Trace except that it traces the error in the given pointer, and
+// it doesn't affect if perr or *perr is nil. Tracep is designed to be deferred like the following:
+// 	func Foo() (err error) {
+// 		defer erf.Tracep(&err)
+// 		...
+// 	}
+func Tracep(perr *error) {
+	if perr == nil {
+		return
+	}
+	err := *perr
+	if err == nil {
+		return
+	}
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) < 1 {
+		return
+	}
+	*perr = trace(err, pc[0])
+}
+
+func trace(err error, pc uintptr) error {
+	if countHelpers([]uintptr{pc}) > 0 {
+		return err
+	}
+	e, ok := err.(*Erf)
+	if !ok {
+		e2 := newWrap(err)
+		e2.returnPC = []uintptr{pc}
+		return e2
+	}
+	e2 := e.clone()
+	e2.returnPC = make([]uintptr, len(e.returnPC), len(e.returnPC)+1)
+	copy(e2.returnPC, e.returnPC)
+	e2.returnPC = append(e2.returnPC, pc)
+	return e2
+}
+
+// ReturnTrace returns the return trace of Erf as a StackTrace, the first StackCaller is the innermost function that
+// the error is traced. It returns nil if the error wasn't traced by Trace or Tracep.
+func (e *Erf) ReturnTrace() *StackTrace {
+	if e.returnST != nil {
+		return e.returnST
+	}
+	if len(e.returnPC) <= 0 {
+		return nil
+	}
+	return NewStackTrace(e.returnPC...)
+}
+
+// clone creates a shallow copy of Erf that shares the immutable fields with e, and derives from the origin of e.
+func (e *Erf) clone() *Erf {
+	e2 := &Erf{
+		err:         e.err,
+		format:      e.format,
+		args:        e.args,
+		tags:        e.tags,
+		tagIndexes:  e.tagIndexes,
+		attrs:       e.attrs,
+		diags:       e.diags,
+		code:        e.code,
+		kind:        e.kind,
+		pc:          e.pc,
+		captured:    e.captured,
+		truncated:   e.truncated,
+		syntheticST: e.syntheticST,
+		returnPC:    e.returnPC,
+		returnST:    e.returnST,
+		spawn:       e.spawn,
+		origin:      e.origin,
+	}
+	if e2.origin == nil {
+		e2.origin = e
+	}
+	return e2
+}
@@ -0,0 +1,82 @@
+package erf_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goinsane/erf"
+)
+
+var errExampleTrace = erf.CaptureNone.New("an example erf error")
+
+func exampleTraceFoo() (err error) {
+	defer erf.Tracep(&err)
+	return errExampleTrace
+}
+
+func exampleTraceBar() (err error) {
+	defer erf.Tracep(&err)
+	return exampleTraceFoo()
+}
+
+func ExampleTracep() {
+	err := exampleTraceBar()
+	rt := err.(*erf.Erf).ReturnTrace()
+
+	fmt.Println("the error matches the original error.")
+	fmt.Println(errors.Is(err, errExampleTrace))
+
+	fmt.Println("the functions that the error travelled back through.")
+	for i := 0; i < rt.Len(); i++ {
+		fmt.Println(rt.Caller(i).Function)
+	}
+
+	fmt.Println("show the return trace after StackTrace.")
+	printLines(fmt.Sprintf("%#x", err))
+
+	// Output:
+	// the error matches the original error.
+	// true
+	// the functions that the error travelled back through.
+	// github.com/goinsane/erf_test.exampleTraceFoo
+	// github.com/goinsane/erf_test.exampleTraceBar
+	// show the return trace after StackTrace.
+	// 	an example erf error
+	// * stack trace not captured
+	// < return trace
+	// github.com/goinsane/erf_test.exampleTraceFoo(0x?)
+	// 	trace_test.go:? +0x?
+	// github.com/goinsane/erf_test.exampleTraceBar(0x?)
+	// 	trace_test.go:? +0x?
+}
+
+var errExampleTracePlain = errors.New("an example error")
+
+func exampleTraceBaz() error {
+	return erf.Trace(errExampleTracePlain)
+}
+
+func exampleTraceQux() error {
+	return erf.Trace(exampleTraceBaz())
+}
+
+func ExampleTrace() {
+	err := exampleTraceQux()
+	e := err.(*erf.Erf)
+	rt := e.ReturnTrace()
+
+	fmt.Println("the error matches the original error, and the stack isn't captured.")
+	fmt.Println(errors.Is(err, errExampleTracePlain), e.Captured())
+
+	fmt.Println("the functions that the error travelled back through.")
+	for i := 0; i < rt.Len(); i++ {
+		fmt.Println(rt.Caller(i).Function)
+	}
+
+	// Output:
+	// the error matches the original error, and the stack isn't captured.
+	// true false
+	// the functions that the error travelled back through.
+	// github.com/goinsane/erf_test.exampleTraceBaz
+	// github.com/goinsane/erf_test.exampleTraceQux
+}
@@ -1,12 +1,87 @@
 package erf
 
 import (
+	"bytes"
 	"fmt"
 	"go/build"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+var (
+	helpers    sync.Map
+	helpersLen int32
+)
+
+var (
+	bufferPool = &sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}
+	pcPool = &sync.Pool{
+		New: func() interface{} {
+			pc := make([]uintptr, DefaultPCSize+1)
+			return &pc
+		},
+	}
+)
+
+const maxPooledBufferSize = 64 * 1024
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+func writePadding(buf *bytes.Buffer, pad byte, n int) {
+	for i := 0; i < n; i++ {
+		buf.WriteByte(pad)
+	}
+}
+
+func writeLines(buf *bytes.Buffer, s string, pad byte, n int) {
+	for {
+		i := strings.IndexByte(s, '\n')
+		writePadding(buf, pad, n)
+		if i < 0 {
+			buf.WriteString(s)
+			buf.WriteRune('\n')
+			return
+		}
+		buf.WriteString(s[:i])
+		buf.WriteRune('\n')
+		s = s[i+1:]
+	}
+}
+
+func writeHex(buf *bytes.Buffer, x uintptr) {
+	var b [2 + 16]byte
+	buf.Write(strconv.AppendUint(append(b[:0], "0x"...), uint64(x), 16))
+}
+
+func writeInt(buf *bytes.Buffer, x int) {
+	var b [20]byte
+	buf.Write(strconv.AppendInt(b[:0], int64(x), 10))
+}
+
+func writeQuote(buf *bytes.Buffer, s string) {
+	var b [64]byte
+	buf.Write(strconv.AppendQuote(b[:0], s))
+}
+
 func trimSrcPath(s string) string {
 	var r string
 	r = strings.TrimPrefix(s, build.Default.GOROOT+"/src/")
@@ -43,3 +118,93 @@ func getPadWidPrec(f fmt.State) (pad byte, wid, prec int) {
 	}
 	return
 }
+
+func unwrapErrors(err error) []error {
+	var errs []error
+	switch err := err.(type) {
+	case interface{ UnwrapMulti() []error }:
+		return err.UnwrapMulti()
+	case MultiWrappedError:
+		errs = err.Unwrap()
+	case WrappedError:
+		errs = []error{err.Unwrap()}
+	}
+	result := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	if len(result) <= 0 {
+		return nil
+	}
+	return result
+}
+
+func walkErrors(err error, depth, branch int, fn func(err error, depth, branch int) bool) {
+	if !fn(err, depth, branch) {
+		return
+	}
+	errs := unwrapErrors(err)
+	if len(errs) > 1 {
+		branch++
+	}
+	for _, err := range errs {
+		walkErrors(err, depth+1, branch, fn)
+	}
+}
+
+// pcFunction returns the name of the outermost function of the given program counter.
+func pcFunction(pc uintptr) string {
+	var frame runtime.Frame
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for more := true; more; {
+		frame, more = frames.Next()
+	}
+	return frame.Function
+}
+
+func isHelper(pc uintptr) bool {
+	_, ok := helpers.Load(pcFunction(pc))
+	return ok
+}
+
+func countHelpers(pc []uintptr) int {
+	if atomic.LoadInt32(&helpersLen) <= 0 {
+		return 0
+	}
+	for i := range pc {
+		if !isHelper(pc[i]) {
+			return i
+		}
+	}
+	return len(pc)
+}
+
+func capturePC(size, skip int) (pc []uintptr, truncated bool) {
+	p := pcPool.Get().(*[]uintptr)
+	defer pcPool.Put(p)
+	if len(*p) < size+1 {
+		*p = make([]uintptr, size+1)
+	}
+	var buf []uintptr
+	for {
+		buf = (*p)[:runtime.Callers(skip, (*p)[:size+1])]
+		n := countHelpers(buf)
+		if n <= 0 {
+			break
+		}
+		if len(buf) <= size {
+			buf = buf[n:]
+			break
+		}
+		skip += n
+	}
+	if len(buf) > size {
+		buf = buf[:size]
+		truncated = true
+	}
+	pc = make([]uintptr, len(buf))
+	copy(pc, buf)
+	return pc, truncated
+}